@@ -0,0 +1,317 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fcavani/e"
+)
+
+// DefaultRecentSize is the number of entries kept by Recent if Recent.Size is
+// zero.
+var DefaultRecentSize = 100
+
+// Recent is an io.WriteCloser that keeps the last entries written in a ring
+// buffer. If Writter isn't nil the entries are also written to it, so Recent
+// can wrap the Slog Writter:
+//
+//	recent := &slog.Recent{Size: 500, Writter: os.Stdout}
+//	logger := &slog.Slog{Writter: recent}
+type Recent struct {
+	// Size is the number of entries kept.
+	Size int
+	// Writter receives the entries too, can be nil.
+	Writter io.WriteCloser
+
+	mu      sync.Mutex
+	entries [][]byte
+	next    int
+	full    bool
+}
+
+// Write stores a copy of p and writes it to Writter.
+func (r *Recent) Write(p []byte) (n int, err error) {
+	r.mu.Lock()
+	if r.entries == nil {
+		if r.Size <= 0 {
+			r.Size = DefaultRecentSize
+		}
+		r.entries = make([][]byte, r.Size)
+	}
+	r.entries[r.next] = append(r.entries[r.next][:0], p...)
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+	r.mu.Unlock()
+	if r.Writter == nil {
+		return len(p), nil
+	}
+	n, err = r.Writter.Write(p)
+	if err != nil {
+		return n, e.Forward(err)
+	}
+	return n, nil
+}
+
+// Entries returns a copy of the last n entries, the oldest first. If n is less
+// or equal to zero all entries are returned.
+func (r *Recent) Entries(n int) [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+	out := make([][]byte, 0, n)
+	for i := count - n; i < count; i++ {
+		j := i
+		if r.full {
+			j = (r.next + i) % len(r.entries)
+		}
+		out = append(out, append([]byte(nil), r.entries[j]...))
+	}
+	return out
+}
+
+// Flush flushes Writter if it is a Flusher.
+func (r *Recent) Flush() error {
+	if f, ok := r.Writter.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close closes Writter.
+func (r *Recent) Close() error {
+	if r.Writter == nil {
+		return nil
+	}
+	return r.Writter.Close()
+}
+
+// Admin is an http.Handler to inspect and change the levels of the running
+// loggers. GET returns the levels as json, PUT replaces all levels and POST
+// changes only the levels in the request, a empty level removes the domain or
+// the tag. The global level is the level in use by the default logger and is
+// changed with its ChangeLevel, PUT without it keeps the level. The body of PUT
+// and POST is json, like the one returned by GET, or a level spec like the one
+// parsed by ParseLevelSpec if the content type isn't application/json. If
+// Recent isn't nil, GET in the path ending in /entries returns the last
+// entries, the query parameter n limits the number of entries. If Filter isn't
+// nil, GET in the path ending in /filter returns the filter expression and PUT
+// or POST replaces it with the expression in the body.
+//
+//	http.Handle("/debug/slog/", &slog.Admin{Recent: recent, Filter: filter})
+type Admin struct {
+	// Recent stores the entries returned in /entries.
+	Recent *Recent
+	// Filter is the filter changed in /filter.
+	Filter *FilterExpr
+
+	mu sync.Mutex
+}
+
+type adminLevels struct {
+	Level   string            `json:"level,omitempty"`
+	Domains map[string]string `json:"domains"`
+	Tags    map[string]string `json:"tags"`
+}
+
+func toAdminLevels(s *LevelSpec) *adminLevels {
+	out := &adminLevels{
+		Domains: make(map[string]string, len(s.Domains)),
+		Tags:    make(map[string]string, len(s.Tags)),
+	}
+	if s.Level != 0 {
+		out.Level = s.Level.String()
+	}
+	for domain, level := range s.Domains {
+		out.Domains[domain] = level.String()
+	}
+	for tag, level := range s.Tags {
+		out.Tags[tag] = level.String()
+	}
+	return out
+}
+
+func parseLevels(in map[string]string, out map[string]Level) error {
+	for key, str := range in {
+		if str == "" {
+			delete(out, key)
+			continue
+		}
+		level, err := ParseLevel(str)
+		if err != nil {
+			return e.Push(err, e.New("invalid level for %q", key))
+		}
+		out[key] = level
+	}
+	return nil
+}
+
+// merge changes s with the levels of a.
+func (a *adminLevels) merge(s *LevelSpec) error {
+	if a.Level != "" {
+		level, err := ParseLevel(a.Level)
+		if err != nil {
+			return e.Push(err, e.New("invalid default level"))
+		}
+		s.Level = level
+	}
+	err := parseLevels(a.Domains, s.Domains)
+	if err != nil {
+		return err
+	}
+	return parseLevels(a.Tags, s.Tags)
+}
+
+func readSpec(r *http.Request) (*LevelSpec, *adminLevels, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, nil, e.Forward(err)
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		levels := new(adminLevels)
+		err = json.Unmarshal(body, levels)
+		if err != nil {
+			return nil, nil, e.Forward(err)
+		}
+		return nil, levels, nil
+	}
+	s, err := ParseLevelSpec(string(body))
+	if err != nil {
+		return nil, nil, e.Forward(err)
+	}
+	return s, nil, nil
+}
+
+func (a *Admin) change(r *http.Request) error {
+	s, levels, err := readSpec(r)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	current := &LevelSpec{
+		Domains: make(map[string]Level),
+		Tags:    make(map[string]Level),
+	}
+	if r.Method == http.MethodPost {
+		current = CurrentLevelSpec()
+	}
+	if levels == nil {
+		levels = toAdminLevels(s)
+	}
+	err = levels.merge(current)
+	if err != nil {
+		return err
+	}
+	if current.Level != 0 {
+		DefaultLogger().ChangeLevel(current.Level)
+		current.Level = 0
+	}
+	current.Apply()
+	return nil
+}
+
+func (a *Admin) writeLevels(w http.ResponseWriter) {
+	buf, err := json.Marshal(toAdminLevels(CurrentLevelSpec()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf)
+}
+
+func (a *Admin) writeEntries(w http.ResponseWriter, r *http.Request) {
+	if a.Recent == nil {
+		http.NotFound(w, r)
+		return
+	}
+	n := 0
+	if str := r.URL.Query().Get("n"); str != "" {
+		var err error
+		n, err = strconv.Atoi(str)
+		if err != nil {
+			http.Error(w, "invalid n: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, entry := range a.Recent.Entries(n) {
+		w.Write(entry)
+	}
+}
+
+func (a *Admin) serveFilter(w http.ResponseWriter, r *http.Request) {
+	if a.Filter == nil {
+		http.NotFound(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = a.Filter.Set(strings.TrimSpace(string(body)))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(a.Filter.String() + "\n"))
+}
+
+// ServeHTTP serves the levels, the recent entries and the filter.
+func (a *Admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/filter") {
+		a.serveFilter(w, r)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/entries") {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		a.writeEntries(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		err := a.change(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	a.writeLevels(w)
+}
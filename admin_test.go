@@ -0,0 +1,183 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func TestRecent(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+	recent := &Recent{Size: 3, Writter: buf}
+
+	for _, s := range []string{"a\n", "b\n"} {
+		_, err := recent.Write([]byte(s))
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+	}
+	if got := string(bytes.Join(recent.Entries(0), nil)); got != "a\nb\n" {
+		t.Fatalf("wrong entries %q", got)
+	}
+	for _, s := range []string{"c\n", "d\n"} {
+		_, err := recent.Write([]byte(s))
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+	}
+	if got := string(bytes.Join(recent.Entries(0), nil)); got != "b\nc\nd\n" {
+		t.Fatalf("wrong entries %q", got)
+	}
+	if got := string(bytes.Join(recent.Entries(2), nil)); got != "c\nd\n" {
+		t.Fatalf("wrong entries %q", got)
+	}
+	if buf.String() != "a\nb\nc\nd\n" {
+		t.Fatalf("wrong writter content %q", buf.String())
+	}
+}
+
+func adminRequest(h http.Handler, method, path, contentType, body string) (int, string) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code, rec.Body.String()
+}
+
+func TestAdmin(t *testing.T) {
+	recent := &Recent{Size: 10}
+	err := SetOutput("api", InfoPrio, recent, nil, nil, 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	logger := DefaultLogger()
+	defer (&LevelSpec{}).Apply()
+
+	admin := &Admin{Recent: recent}
+
+	// Without spec the level is the one of the default logger.
+	code, body := adminRequest(admin, "GET", "/debug/slog/levels", "", "")
+	if code != http.StatusOK || !strings.Contains(body, `"level":"info"`) {
+		t.Fatal("wrong response", code, body)
+	}
+	SetLevel(ErrorPrio)
+	code, body = adminRequest(admin, "GET", "/debug/slog/levels", "", "")
+	if code != http.StatusOK || !strings.Contains(body, `"level":"error"`) {
+		t.Fatal("wrong response", code, body)
+	}
+	code, body = adminRequest(admin, "POST", "/debug/slog/levels", "application/json", `{"level":"debug"}`)
+	if code != http.StatusOK || !logger.Enabled(DebugPrio) {
+		t.Fatal("level not changed", code, body)
+	}
+	logger.ChangeLevel(InfoPrio)
+	if logger.Enabled(DebugPrio) {
+		t.Fatal("ChangeLevel masked by the admin level")
+	}
+
+	code, body = adminRequest(admin, "PUT", "/debug/slog/levels", "", "warn,api.db=debug")
+	if code != http.StatusOK {
+		t.Fatal("wrong status", code, body)
+	}
+
+	levels := make(map[string]interface{})
+	err = json.Unmarshal([]byte(body), &levels)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	if levels["level"] != "warning" || levels["domains"].(map[string]interface{})["api.db"] != "debug" {
+		t.Fatal("wrong levels", body)
+	}
+
+	logger.Print("discarded")
+	logger.Sub("db").DebugLevel().Print("db debug")
+	logger.Warn("warn")
+
+	code, body = adminRequest(admin, "POST", "/debug/slog/levels", "application/json", `{"domains":{"api.db":"","api.cache":"proto"},"tags":{"x":"error"}}`)
+	if code != http.StatusOK {
+		t.Fatal("wrong status", code, body)
+	}
+	if s := CurrentLevelSpec().String(); s != "warning,api.cache=protocol,tag:x=error" {
+		t.Fatal("wrong spec", s)
+	}
+
+	logger.Sub("db").DebugLevel().Print("discarded")
+
+	code, body = adminRequest(admin, "GET", "/debug/slog/levels", "", "")
+	if code != http.StatusOK || !strings.Contains(body, `"api.cache":"protocol"`) {
+		t.Fatal("wrong response", code, body)
+	}
+
+	code, body = adminRequest(admin, "GET", "/debug/slog/entries", "", "")
+	if code != http.StatusOK {
+		t.Fatal("wrong status", code, body)
+	}
+	lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "debug - db debug") || !strings.HasSuffix(lines[1], "warning - warn") {
+		t.Fatalf("wrong entries %q", body)
+	}
+
+	code, body = adminRequest(admin, "GET", "/debug/slog/entries?n=1", "", "")
+	if code != http.StatusOK || strings.Count(body, "\n") != 1 {
+		t.Fatalf("wrong entries %v %q", code, body)
+	}
+
+	code, _ = adminRequest(admin, "PUT", "/debug/slog/levels", "", "api=bogus")
+	if code != http.StatusBadRequest {
+		t.Fatal("wrong status", code)
+	}
+	code, _ = adminRequest(admin, "POST", "/debug/slog/levels", "application/json", `{"level":"bogus"}`)
+	if code != http.StatusBadRequest {
+		t.Fatal("wrong status", code)
+	}
+	code, _ = adminRequest(admin, "DELETE", "/debug/slog/levels", "", "")
+	if code != http.StatusMethodNotAllowed {
+		t.Fatal("wrong status", code)
+	}
+	code, _ = adminRequest(&Admin{}, "GET", "/debug/slog/entries", "", "")
+	if code != http.StatusNotFound {
+		t.Fatal("wrong status", code)
+	}
+}
+
+func TestAdminFilter(t *testing.T) {
+	filter := new(FilterExpr)
+	admin := &Admin{Filter: filter}
+
+	code, body := adminRequest(admin, "PUT", "/debug/slog/filter", "", "level>=error || tag:db\n")
+	if code != http.StatusOK || body != "level>=error || tag:db\n" {
+		t.Fatalf("wrong response %v %q", code, body)
+	}
+	if filter.String() != "level>=error || tag:db" {
+		t.Fatal("filter not set", filter.String())
+	}
+
+	code, body = adminRequest(admin, "POST", "/debug/slog/filter", "", "level>=")
+	if code != http.StatusBadRequest || !strings.Contains(body, "expected a level but found end of expression at column 8") {
+		t.Fatalf("wrong response %v %q", code, body)
+	}
+	code, body = adminRequest(admin, "GET", "/debug/slog/filter", "", "")
+	if code != http.StatusOK || body != "level>=error || tag:db\n" {
+		t.Fatalf("wrong response %v %q", code, body)
+	}
+
+	code, _ = adminRequest(admin, "DELETE", "/debug/slog/filter", "", "")
+	if code != http.StatusMethodNotAllowed {
+		t.Fatal("wrong status", code)
+	}
+	code, _ = adminRequest(&Admin{}, "GET", "/debug/slog/filter", "", "")
+	if code != http.StatusNotFound {
+		t.Fatal("wrong status", code)
+	}
+}
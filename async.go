@@ -0,0 +1,221 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy defines what Async does when the queue is full.
+type OverflowPolicy uint8
+
+const (
+	// Block waits until there is room in the queue.
+	Block OverflowPolicy = iota
+	// DropNewest discards the entry being committed.
+	DropNewest
+	// DropOldest discards the oldest entry in the queue to make room to the
+	// new one.
+	DropOldest
+	// DropBelowLevel discards the entry being committed if its priority is
+	// less than Async.DropLevel, otherwise it blocks.
+	DropBelowLevel
+)
+
+// DefaultAsyncSize is the size of the Async queue if Async.Size is zero.
+var DefaultAsyncSize = 1024
+
+type asyncEntry struct {
+	buf  []byte
+	prio Level
+	w    io.Writer
+	lck  *sync.Mutex
+}
+
+// Async is a committer that formats the entries in the caller goroutine and
+// queues them in a bounded ring buffer. A background goroutine drains the
+// queue to the Writter of each entry, so a slow writer doesn't stall the
+// goroutines that log. Use the Commit method as the Slog Commit function and
+// set the Flusher, so Fatal and Panic wait the queue to be drained:
+//
+//	async := &slog.Async{Size: 4096, Policy: slog.DropOldest}
+//	logger := &slog.Slog{Commit: async.Commit, Flusher: async}
+type Async struct {
+	// Size is the number of entries the queue holds.
+	Size int
+	// Policy is the overflow policy used when the queue is full.
+	Policy OverflowPolicy
+	// DropLevel is the minimum priority that isn't dropped with the
+	// DropBelowLevel policy.
+	DropLevel Level
+
+	once    sync.Once
+	mu      sync.Mutex
+	cond    *sync.Cond
+	ring    []asyncEntry
+	head    int
+	count   int
+	writing bool
+	closed  bool
+	done    chan struct{}
+	dropped uint64
+}
+
+func (a *Async) init() {
+	a.once.Do(func() {
+		if a.Size <= 0 {
+			a.Size = DefaultAsyncSize
+		}
+		a.ring = make([]asyncEntry, a.Size)
+		a.cond = sync.NewCond(&a.mu)
+		a.done = make(chan struct{})
+		go a.run()
+	})
+}
+
+// Commit formats the entry and put it in the queue.
+func (a *Async) Commit(sl *Slog) {
+	a.init()
+	sl.stamp()
+	if sl.Log.DoDi && sl.Log.file == "" {
+		sl.Log.file = debugInfo(sl.Log.DiLevel)
+	}
+	buf, err := sl.Formatter(sl)
+	if err != nil {
+		//TODO: Give to the user a nice error message.
+		println("SLOG writer failed:", err)
+		return
+	}
+	a.enqueue(asyncEntry{
+		buf:  buf,
+		prio: sl.Log.Priority,
+		w:    sl.Writter,
+		lck:  sl.Lck,
+	})
+}
+
+func (a *Async) drop(ent asyncEntry) {
+	atomic.AddUint64(&a.dropped, 1)
+	Pool.Put(ent.buf[:0])
+}
+
+func (a *Async) enqueue(ent asyncEntry) {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		// The queue is closed, write synchronously.
+		write(ent)
+		return
+	}
+	for a.count == len(a.ring) {
+		switch a.Policy {
+		case DropNewest:
+			a.mu.Unlock()
+			a.drop(ent)
+			return
+		case DropOldest:
+			old := a.ring[a.head]
+			a.ring[a.head] = asyncEntry{}
+			a.head = (a.head + 1) % len(a.ring)
+			a.count--
+			a.drop(old)
+			continue
+		case DropBelowLevel:
+			if ent.prio < a.DropLevel {
+				a.mu.Unlock()
+				a.drop(ent)
+				return
+			}
+		}
+		a.cond.Wait()
+		if a.closed {
+			a.mu.Unlock()
+			write(ent)
+			return
+		}
+	}
+	a.ring[(a.head+a.count)%len(a.ring)] = ent
+	a.count++
+	a.cond.Broadcast()
+	a.mu.Unlock()
+}
+
+func write(ent asyncEntry) {
+	if ent.lck != nil {
+		ent.lck.Lock()
+		defer ent.lck.Unlock()
+	}
+	_, err := ent.w.Write(ent.buf)
+	if err != nil {
+		println("SLOG writer failed:", err)
+	}
+	Pool.Put(ent.buf[:0])
+}
+
+func (a *Async) run() {
+	defer close(a.done)
+	a.mu.Lock()
+	for {
+		for a.count == 0 && !a.closed {
+			a.cond.Wait()
+		}
+		if a.count == 0 {
+			a.mu.Unlock()
+			return
+		}
+		ent := a.ring[a.head]
+		a.ring[a.head] = asyncEntry{}
+		a.head = (a.head + 1) % len(a.ring)
+		a.count--
+		a.writing = true
+		a.cond.Broadcast()
+		a.mu.Unlock()
+
+		write(ent)
+
+		a.mu.Lock()
+		a.writing = false
+		if a.count == 0 {
+			a.cond.Broadcast()
+		}
+	}
+}
+
+// Flush waits until all queued entries are written.
+func (a *Async) Flush() error {
+	a.init()
+	a.mu.Lock()
+	for a.count > 0 || a.writing {
+		a.cond.Wait()
+	}
+	a.mu.Unlock()
+	return nil
+}
+
+// Dropped returns the number of entries discarded by the overflow policy.
+func (a *Async) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Len returns the number of entries in the queue.
+func (a *Async) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.count
+}
+
+// Close writes the entries in the queue and stops the background goroutine.
+// Entries committed after Close are written synchronously.
+func (a *Async) Close() error {
+	a.init()
+	a.mu.Lock()
+	a.closed = true
+	a.cond.Broadcast()
+	a.mu.Unlock()
+	<-a.done
+	return nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+// gateWriter blocks the writes until the gate is opened.
+type gateWriter struct {
+	writerCloser
+	mu   sync.Mutex
+	gate chan struct{}
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{
+		writerCloser: writerCloser{bytes.NewBuffer([]byte{})},
+		gate:         make(chan struct{}),
+	}
+}
+
+func (g *gateWriter) Write(p []byte) (int, error) {
+	<-g.gate
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.writerCloser.Write(p)
+}
+
+func (g *gateWriter) Open() {
+	close(g.gate)
+}
+
+func (g *gateWriter) Lines() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var lines []string
+	for _, l := range strings.Split(g.String(), "\n") {
+		if l == "" {
+			continue
+		}
+		s := strings.Split(l, " - ")
+		lines = append(lines, s[len(s)-1])
+	}
+	return lines
+}
+
+func TestAsync(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+	async := &Async{Size: 4}
+
+	logger := &Slog{
+		Writter: buf,
+		Level:   DebugPrio,
+		Commit:  async.Commit,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	logger = logger.Di().MakeDefault()
+	logger.Tag("tag1").Println(msg)
+	async.Flush()
+	AssertLine(t, buf, "teste - info - tag1 - slog/async_test.go:72 - benchmark log test")
+
+	for i := 0; i < 100; i++ {
+		logger.NoDi().Println(msg)
+	}
+	async.Flush()
+	if n := strings.Count(buf.String(), "\n"); n != 100 {
+		t.Fatal("wrong number of lines", n)
+	}
+	buf.Reset()
+	if async.Dropped() != 0 {
+		t.Fatal("dropped entries")
+	}
+
+	err = async.Close()
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	logger.NoDi().Println(msg)
+	AssertLine(t, buf, "teste - info - benchmark log test")
+}
+
+func newAsyncLogger(t *testing.T, async *Async) (*Slog, *gateWriter) {
+	w := newGateWriter()
+	logger := &Slog{
+		Writter: w,
+		Level:   DebugPrio,
+		Commit:  async.Commit,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	// The first entry is taken by the background goroutine and blocks in
+	// the writer, after that the queue is empty.
+	logger.Print("0")
+	for async.Len() != 0 {
+		runtime.Gosched()
+	}
+	return logger, w
+}
+
+func TestAsyncDropNewest(t *testing.T) {
+	async := &Async{Size: 2, Policy: DropNewest}
+	logger, w := newAsyncLogger(t, async)
+	for _, m := range []string{"1", "2", "3", "4"} {
+		logger.Print(m)
+	}
+	w.Open()
+	async.Flush()
+	if lines := w.Lines(); strings.Join(lines, ",") != "0,1,2" {
+		t.Fatal("wrong entries", lines)
+	}
+	if async.Dropped() != 2 {
+		t.Fatal("wrong dropped count", async.Dropped())
+	}
+}
+
+func TestAsyncDropOldest(t *testing.T) {
+	async := &Async{Size: 2, Policy: DropOldest}
+	logger, w := newAsyncLogger(t, async)
+	for _, m := range []string{"1", "2", "3", "4"} {
+		logger.Print(m)
+	}
+	w.Open()
+	async.Flush()
+	if lines := w.Lines(); strings.Join(lines, ",") != "0,3,4" {
+		t.Fatal("wrong entries", lines)
+	}
+	if async.Dropped() != 2 {
+		t.Fatal("wrong dropped count", async.Dropped())
+	}
+}
+
+func TestAsyncDropBelowLevel(t *testing.T) {
+	async := &Async{Size: 2, Policy: DropBelowLevel, DropLevel: ErrorPrio}
+	logger, w := newAsyncLogger(t, async)
+	logger.Print("1")
+	logger.Print("2")
+	logger.Print("3")
+	logger.DebugLevel().Print("4")
+	if async.Dropped() != 2 {
+		t.Fatal("wrong dropped count", async.Dropped())
+	}
+	done := make(chan struct{})
+	go func() {
+		// Blocks until there is room in the queue.
+		logger.Error("5")
+		close(done)
+	}()
+	w.Open()
+	<-done
+	async.Flush()
+	if lines := w.Lines(); strings.Join(lines, ",") != "0,1,2,5" {
+		t.Fatal("wrong entries", lines)
+	}
+}
+
+func TestAsyncBlock(t *testing.T) {
+	async := &Async{Size: 1}
+	logger, w := newAsyncLogger(t, async)
+	logger.Print("1")
+	done := make(chan struct{})
+	go func() {
+		logger.Print("2")
+		close(done)
+	}()
+	w.Open()
+	<-done
+	async.Flush()
+	if lines := w.Lines(); strings.Join(lines, ",") != "0,1,2" {
+		t.Fatal("wrong entries", lines)
+	}
+	if async.Dropped() != 0 {
+		t.Fatal("wrong dropped count", async.Dropped())
+	}
+}
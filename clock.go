@@ -0,0 +1,40 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"time"
+)
+
+// Clock gives the time of the entries. Replace the Slog Clock with a fake
+// clock to have deterministic timestamps in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// RealClock is the Clock that returns time.Now().
+var RealClock Clock = realClock{}
+
+// now returns the time of an entry committed now.
+func (l *Slog) now() time.Time {
+	if l.Clock == nil {
+		return time.Now()
+	}
+	return l.Clock.Now()
+}
+
+// stamp sets the timestamp of the entry to now if it isn't set yet, Handle
+// sets it to the time of the record.
+func (l *Slog) stamp() {
+	if l.Log.Timestamp.IsZero() {
+		l.Log.Timestamp = l.now()
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// config is the configuration shared by a logger and all the copies in its
+// pool. It's changed atomically, so the changes are seen by all copies,
+// even the ones in use by other goroutines.
+type config struct {
+	level  int32
+	exiter atomic.Value
+}
+
+func newConfig(level Level, exiter func(int)) *config {
+	c := &config{
+		level: int32(level),
+	}
+	c.exiter.Store(exiter)
+	return c
+}
+
+func (c *config) getLevel() Level {
+	return Level(atomic.LoadInt32(&c.level))
+}
+
+func (c *config) setLevel(level Level) {
+	atomic.StoreInt32(&c.level, int32(level))
+}
+
+func (c *config) getExiter() func(int) {
+	return c.exiter.Load().(func(int))
+}
+
+func (c *config) setExiter(fn func(int)) {
+	c.exiter.Store(fn)
+}
+
+// baseLevel returns the level of l, without the domain and tag levels.
+func (l *Slog) baseLevel() Level {
+	if l.cfg == nil {
+		return l.Level
+	}
+	return l.cfg.getLevel()
+}
+
+// ChangeLevel changes the level of l and of all loggers that share its
+// configuration: the copies returned by the chain of methods and the loggers
+// returned by MakeDefault. Unlike SetLevel, that sets the level of one entry,
+// the change is seen immediately by all goroutines. It's safe to call
+// ChangeLevel while other goroutines are logging.
+func (l *Slog) ChangeLevel(level Level) {
+	if level == 0 {
+		level = InfoPrio
+	}
+	l.cfg.setLevel(level)
+}
+
+// ChangeExiter changes the function called to exit by Fatal and Panic for l
+// and all loggers that share its configuration. It's safe to call ChangeExiter
+// while other goroutines are logging.
+func (l *Slog) ChangeExiter(fn func(int)) {
+	if fn == nil {
+		fn = os.Exit
+	}
+	l.cfg.setExiter(fn)
+}
@@ -0,0 +1,128 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func TestChangeLevel(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter: buf,
+		Level:   InfoPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	def := logger.Tag("tag1").MakeDefault()
+	own := logger.SetLevel(ErrorPrio).MakeDefault()
+
+	logger.ChangeLevel(DebugPrio)
+
+	logger.DebugLevel().Print(msg)
+	AssertLine(t, buf, "teste - debug - benchmark log test")
+
+	def.DebugLevel().Print(msg)
+	AssertLine(t, buf, "teste - debug - tag1 - benchmark log test")
+
+	own.Warn(msg)
+	AssertEOF(t, buf)
+
+	logger.ChangeLevel(WarnPrio)
+
+	def.Print(msg)
+	AssertEOF(t, buf)
+
+	logger.SetLevel(InfoPrio).Print(msg)
+	AssertLine(t, buf, "teste - info - benchmark log test")
+
+	logger.Print(msg)
+	AssertEOF(t, buf)
+}
+
+func TestChangeExiter(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	var first, second int32
+	logger := &Slog{
+		Writter: buf,
+		Level:   InfoPrio,
+		Exiter: func(int) {
+			atomic.AddInt32(&first, 1)
+		},
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	def := logger.Tag("tag1").MakeDefault()
+
+	logger.ChangeExiter(func(int) {
+		atomic.AddInt32(&second, 1)
+	})
+
+	def.Fatal(msg)
+	AssertLine(t, buf, "teste - fatal - tag1 - benchmark log test")
+	if first != 0 || second != 1 {
+		t.Fatal("wrong exiter called", first, second)
+	}
+}
+
+func TestChangeLevelConcurrent(t *testing.T) {
+	logger := &Slog{
+		Writter: &writerCloser{bytes.NewBuffer([]byte{})},
+		Level:   InfoPrio,
+	}
+	err := logger.Init("teste", 10)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	err = SetOutput("teste", InfoPrio, &writerCloser{bytes.NewBuffer([]byte{})}, nil, nil, 10)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				logger.Tag("tag1").DebugLevel().Print(msg)
+				DebugLevel().Print(msg)
+				Print(msg)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		logger.ChangeLevel(Level(i%3) + DebugPrio)
+		err = SetLevel(Level(i%3) + DebugPrio)
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+		err = Exiter(func(int) {})
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
@@ -0,0 +1,137 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"context"
+	"sync"
+)
+
+// Keys of the fields added by Ctx with the request and trace ids.
+const (
+	RequestIDKey = "request_id"
+	TraceIDKey   = "trace_id"
+)
+
+type ctxKey uint8
+
+const (
+	loggerKey ctxKey = iota
+	requestIDKey
+	traceIDKey
+)
+
+// Extractor returns the fields that will be added to the entry from values
+// stored in the context.
+type Extractor func(ctx context.Context) []Field
+
+// extractor wraps an Extractor so its registration can be found and removed.
+type extractor struct {
+	fn Extractor
+}
+
+var extractorsLck sync.RWMutex
+var extractors []*extractor
+
+// AddExtractor registers a function that extracts fields from the context.
+// The extractors are called by Ctx in the order that they were registered.
+// The returned function removes the extractor, calling it more than once does
+// nothing.
+//
+//	remove := slog.AddExtractor(userExtractor)
+//	defer remove()
+func AddExtractor(fn Extractor) (remove func()) {
+	ext := &extractor{fn: fn}
+	extractorsLck.Lock()
+	defer extractorsLck.Unlock()
+	extractors = append(extractors, ext)
+	return func() {
+		extractorsLck.Lock()
+		defer extractorsLck.Unlock()
+		for i, x := range extractors {
+			if x == ext {
+				extractors = append(extractors[:i:i], extractors[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// NewContext returns a copy of ctx that carries the logger l. If l is a copy
+// in a chain of calls, like the one returned by Tag, it is made default first.
+func NewContext(ctx context.Context, l *Slog) context.Context {
+	if l.Cp {
+		l = l.MakeDefault()
+	}
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// FromContext returns the logger stored in ctx by NewContext or the default
+// logger if there is none.
+func FromContext(ctx context.Context) *Slog {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey).(*Slog); ok && l != nil {
+			return l
+		}
+	}
+	return log
+}
+
+// WithRequestID returns a copy of ctx that carries the request id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the request id stored in ctx.
+func RequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey).(string)
+	return id, ok
+}
+
+// WithTraceID returns a copy of ctx that carries the trace id.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey, id)
+}
+
+// TraceID returns the trace id stored in ctx.
+func TraceID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(traceIDKey).(string)
+	return id, ok
+}
+
+// addContext adds to f the request id, the trace id and the fields returned by
+// the extractors.
+func (f *fields) addContext(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	if id, ok := RequestID(ctx); ok {
+		f.Add(Field{Key: RequestIDKey, Value: id})
+	}
+	if id, ok := TraceID(ctx); ok {
+		f.Add(Field{Key: TraceIDKey, Value: id})
+	}
+	extractorsLck.RLock()
+	defer extractorsLck.RUnlock()
+	for _, ext := range extractors {
+		f.Add(ext.fn(ctx)...)
+	}
+}
+
+// Ctx add to the log entry the request id, the trace id and the fields
+// extracted from ctx by the extractors registered with AddExtractor.
+func (l *Slog) Ctx(ctx context.Context) *Slog {
+	l = l.copy()
+	l.Log.Fields.addContext(ctx)
+	return l
+}
+
+// Ctx returns the logger stored in ctx, or the default logger, with the fields
+// extracted from ctx.
+//
+//	slog.Ctx(r.Context()).Tag("http").Print("request done")
+func Ctx(ctx context.Context) *Slog {
+	return FromContext(ctx).Ctx(ctx).di(fnLevelDi)
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+type userKey struct{}
+
+func TestContext(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter: buf,
+		Level:   DebugPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	if FromContext(context.Background()) != DefaultLogger() {
+		t.Fatal("FromContext didn't return the default logger")
+	}
+
+	ctx := NewContext(context.Background(), logger.Tag("tag1"))
+	FromContext(ctx).Print(msg)
+	AssertLine(t, buf, "teste - info - tag1 - benchmark log test")
+
+	ctx = WithRequestID(ctx, "req1")
+	ctx = WithTraceID(ctx, "trace1")
+	FromContext(ctx).Ctx(ctx).Print(msg)
+	AssertLine(t, buf, "teste - info - tag1 - request_id=req1 trace_id=trace1 - benchmark log test")
+
+	id, ok := RequestID(ctx)
+	if !ok || id != "req1" {
+		t.Fatal("wrong request id", id)
+	}
+	id, ok = TraceID(ctx)
+	if !ok || id != "trace1" {
+		t.Fatal("wrong trace id", id)
+	}
+
+	remove := AddExtractor(func(ctx context.Context) []Field {
+		user, ok := ctx.Value(userKey{}).(string)
+		if !ok {
+			return nil
+		}
+		return []Field{{Key: "user", Value: user}}
+	})
+	defer remove()
+
+	Ctx(context.WithValue(ctx, userKey{}, "john")).Error(msg)
+	AssertLine(t, buf, "teste - error - tag1 - request_id=req1 trace_id=trace1 user=john - benchmark log test")
+
+	ctx = NewContext(WithRequestID(context.Background(), "req2"), logger)
+	Ctx(ctx).Di().Warn(msg)
+	AssertLine(t, buf, "teste - warning - request_id=req2 - slog/context_test.go:65 - benchmark log test")
+
+	logger.Ctx(context.Background()).Print(msg)
+	AssertLine(t, buf, "teste - info - benchmark log test")
+	remove()
+	remove()
+	logger.Ctx(context.WithValue(ctx, userKey{}, "john")).Print(msg)
+	AssertLine(t, buf, "teste - info - request_id=req2 - benchmark log test")
+}
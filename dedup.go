@@ -0,0 +1,213 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DedupKey selects the parts of the entry that Dedup compares.
+type DedupKey uint8
+
+const (
+	// DedupLevel compares the priority.
+	DedupLevel DedupKey = 1 << iota
+	// DedupDomain compares the domain.
+	DedupDomain
+	// DedupTags compares the tags.
+	DedupTags
+	// DedupMessage compares the message.
+	DedupMessage
+	// DedupFields compares all fields.
+	DedupFields
+	// DedupDefault compares the priority, the domain, the tags and the
+	// message.
+	DedupDefault = DedupLevel | DedupDomain | DedupTags | DedupMessage
+)
+
+// Dedup is a committer that collapses duplicated entries. The first entry is
+// sent to Next and the duplicates are counted. When the run ends, Next
+// receives a copy of the first entry, with the field "repeated", and the
+// message "last message repeated N times", like syslogd, or "message
+// repeated N times: " followed by the message if other entries were committed
+// in between. With Window zero only consecutive duplicates are collapsed and
+// the run ends with the next distinct entry. Otherwise the duplicates in
+// Window after the first entry are collapsed, even if mixed with others, and
+// the run ends with the first entry after Window. The runs only end when an
+// entry is committed or Flush is called, call Flush periodically, like with a
+// time.Ticker, to not delay the counts of idle loggers. Flush ends all runs,
+// set the Dedup as the Slog Flusher to not lose the counts on exit. Next is
+// called without locks, so it may log, even with the same Dedup:
+//
+//	dedup := &slog.Dedup{Window: time.Minute}
+//	logger := &slog.Slog{Commit: dedup.Commit, Flusher: dedup}
+type Dedup struct {
+	// Next is the committer that receives the entries. If nil the entries
+	// are written in the Slog Writter with the Formatter, like the default
+	// Commit.
+	Next func(sl *Slog)
+	// Window is the time the duplicates of an entry are collapsed.
+	Window time.Duration
+	// Key is what is compared, zero is DedupDefault.
+	Key DedupKey
+	// Fields are the keys of the fields compared besides Key.
+	Fields []string
+
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+	queue   []*dedupEntry
+	last    *dedupEntry
+}
+
+type dedupEntry struct {
+	key   string
+	sl    *Slog
+	count uint64
+	end   time.Time
+}
+
+func (d *Dedup) next(sl *Slog) {
+	if d.Next != nil {
+		d.Next(sl)
+		return
+	}
+	commitWriter(sl)
+}
+
+func (d *Dedup) key(buf *[]byte, sl *Slog) {
+	key := d.Key
+	if key == 0 {
+		key = DedupDefault
+	}
+	if key&DedupLevel != 0 {
+		*buf = append(*buf, byte(sl.Log.Priority))
+	}
+	*buf = append(*buf, 0)
+	if key&DedupDomain != 0 {
+		*buf = append(*buf, sl.Log.Domain...)
+	}
+	*buf = append(*buf, 0)
+	if key&DedupTags != 0 {
+		for _, tag := range *sl.Log.Tags {
+			*buf = append(*buf, tag...)
+			*buf = append(*buf, ' ')
+		}
+	}
+	*buf = append(*buf, 0)
+	if key&DedupMessage != 0 {
+		*buf = append(*buf, sl.Log.msg...)
+	}
+	*buf = append(*buf, 0)
+	if key&DedupFields != 0 {
+		sl.Log.Fields.encodeText(buf)
+	}
+	for _, name := range d.Fields {
+		*buf = append(*buf, 0)
+		if v, ok := sl.Log.Fields.Get(name); ok {
+			appendTextValue(buf, v)
+		}
+	}
+}
+
+// Commit sends the entry to Next or counts it if it is a duplicate.
+func (d *Dedup) Commit(sl *Slog) {
+	sl.helper()
+	if sl.Log.DoDi && sl.Log.file == "" {
+		sl.Log.file = debugInfo(sl.Log.DiLevel)
+	}
+	buf := Pool.Get().([]byte)
+	d.key(&buf, sl)
+	now := sl.now()
+
+	// The entries are sent to Next after the lock is released, so a slow
+	// Next doesn't block the other goroutines and Next can log.
+	var ended []*Slog
+	d.mu.Lock()
+	if d.entries == nil {
+		d.entries = make(map[string]*dedupEntry)
+	}
+	if d.Window > 0 {
+		for len(d.queue) > 0 && !now.Before(d.queue[0].end) {
+			ended = d.end(ended, d.queue[0])
+			d.queue[0] = nil
+			d.queue = d.queue[1:]
+		}
+	}
+	if ent, ok := d.entries[string(buf)]; ok {
+		ent.count++
+		d.mu.Unlock()
+		Pool.Put(buf[:0])
+		d.emit(ended)
+		return
+	}
+	if d.Window <= 0 {
+		ended = d.flush(ended)
+	}
+	ent := &dedupEntry{
+		key: string(buf),
+		sl:  sl.dup(),
+		end: now.Add(d.Window),
+	}
+	ent.sl.Cp = true
+	d.entries[ent.key] = ent
+	d.queue = append(d.queue, ent)
+	d.last = ent
+	d.mu.Unlock()
+	Pool.Put(buf[:0])
+
+	d.emit(ended)
+	d.next(sl)
+}
+
+// emit sends the ended entries to Next.
+func (d *Dedup) emit(ended []*Slog) {
+	for _, n := range ended {
+		d.next(n)
+		n.release()
+	}
+}
+
+// end forgets ent and appends to ended the entry with the number of its
+// duplicates, if any.
+func (d *Dedup) end(ended []*Slog, ent *dedupEntry) []*Slog {
+	delete(d.entries, ent.key)
+	n := ent.sl
+	if ent.count == 0 {
+		n.release()
+		return ended
+	}
+	count := strconv.FormatUint(ent.count, 10)
+	if ent == d.last {
+		n.Log.Message("last message repeated " + count + " times")
+	} else {
+		n.Log.Message("message repeated " + count + " times: " + n.Log.msg)
+	}
+	d.last = nil
+	n.Log.Timestamp = time.Time{}
+	n.Log.DoDi = false
+	n.Log.file = ""
+	n.Log.Fields.Add(Field{Key: "repeated", Value: ent.count})
+	return append(ended, n)
+}
+
+func (d *Dedup) flush(ended []*Slog) []*Slog {
+	for i, ent := range d.queue {
+		ended = d.end(ended, ent)
+		d.queue[i] = nil
+	}
+	d.queue = d.queue[:0]
+	return ended
+}
+
+// Flush ends the runs of duplicates, committing their counts.
+func (d *Dedup) Flush() error {
+	d.mu.Lock()
+	ended := d.flush(nil)
+	d.mu.Unlock()
+	d.emit(ended)
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/fcavani/slog"
+)
+
+func TestDedupConsecutive(t *testing.T) {
+	dedup := &Dedup{}
+	logger, buf, _ := newFakeClockLogger(t, &Slog{Commit: dedup.Commit, Flusher: dedup}, "teste")
+
+	for i := 0; i < 4; i++ {
+		logger.Tag("retry").Error("connection refused")
+	}
+	logger.Tag("retry").Warn("connection refused")
+	logger.Tag("retry").Warn("connection refused")
+	logger.Print(msg)
+	logger.Di().Print(msg)
+
+	expected := []string{
+		" - error - retry - connection refused",
+		" - error - retry - repeated=3 - last message repeated 3 times",
+		" - warning - retry - connection refused",
+		" - warning - retry - repeated=1 - last message repeated 1 times",
+		" - info - " + msg,
+	}
+	l := lines(buf)
+	if len(l) != len(expected) {
+		t.Fatal("wrong number of entries", l)
+	}
+	for i := range expected {
+		if !strings.HasSuffix(l[i], expected[i]) {
+			t.Fatalf("wrong entry %v: %q", i, l[i])
+		}
+	}
+
+	err := logger.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l = lines(buf)
+	if len(l) != 1 || !strings.HasSuffix(l[0], " - info - repeated=1 - last message repeated 1 times") {
+		t.Fatal("wrong flush", l)
+	}
+	err = logger.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l = lines(buf); len(l) != 0 {
+		t.Fatal("flushed twice", l)
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	dedup := &Dedup{Window: time.Minute, Fields: []string{"host"}}
+	logger, buf, clock := newFakeClockLogger(t, &Slog{Commit: dedup.Commit, Flusher: dedup}, "teste")
+
+	for i := 0; i < 3; i++ {
+		logger.With("host", "a", "try", i).Error("timeout")
+		logger.With("host", "b").Error("timeout")
+	}
+	l := lines(buf)
+	if len(l) != 2 {
+		t.Fatal("wrong number of entries", l)
+	}
+
+	clock.Add(time.Minute)
+	logger.Print(msg)
+	expected := []string{
+		" - error - host=a try=0 repeated=2 - message repeated 2 times: timeout",
+		" - error - host=b repeated=2 - message repeated 2 times: timeout",
+		" - info - " + msg,
+	}
+	l = lines(buf)
+	if len(l) != len(expected) {
+		t.Fatal("wrong number of entries", l)
+	}
+	for i := range expected {
+		if !strings.HasSuffix(l[i], expected[i]) {
+			t.Fatalf("wrong entry %v: %q", i, l[i])
+		}
+	}
+}
+
+func TestDedupNext(t *testing.T) {
+	var mu sync.Mutex
+	var msgs []string
+	dedup := &Dedup{
+		Key: DedupMessage,
+		Next: func(sl *Slog) {
+			mu.Lock()
+			defer mu.Unlock()
+			buf, err := sl.Formatter(sl)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			msgs = append(msgs, strings.TrimSuffix(string(buf), "\n"))
+		},
+	}
+	logger, _, _ := newFakeClockLogger(t, &Slog{Commit: dedup.Commit, Flusher: dedup}, "teste")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Tag("worker").Print(msg)
+			}
+		}()
+	}
+	wg.Wait()
+	logger.Flush()
+	if len(msgs) != 2 {
+		t.Fatal("wrong number of entries", msgs)
+	}
+	if !strings.HasSuffix(msgs[1], "repeated=399 - last message repeated 399 times") {
+		t.Fatalf("wrong entry %q", msgs[1])
+	}
+}
+
+func TestDedupNextLogs(t *testing.T) {
+	var logger *Slog
+	dedup := &Dedup{}
+	dedup.Next = func(sl *Slog) {
+		buf, err := sl.Formatter(sl)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sl.Writter.Write(buf)
+		if sl.Log.Tags.Have("outer") {
+			logger.Tag("inner").Print("from next")
+		}
+	}
+	logger, buf, _ := newFakeClockLogger(t, &Slog{Commit: dedup.Commit, Flusher: dedup}, "teste")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Tag("outer").Print(msg)
+		logger.Flush()
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("deadlock")
+	}
+	expected := []string{
+		" - info - outer - " + msg,
+		" - info - inner - from next",
+	}
+	l := lines(buf)
+	if len(l) != len(expected) {
+		t.Fatal("wrong number of entries", l)
+	}
+	for i := range expected {
+		if !strings.HasSuffix(l[i], expected[i]) {
+			t.Fatalf("wrong entry %v: %q", i, l[i])
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DomainSep separates the names in the domain of a sub logger.
+const DomainSep = '.'
+
+var domainsLck sync.RWMutex
+var domains = make(map[string]Level)
+var numDomains int32
+
+// SetDomainLevel sets the level of the loggers which domain is prefix or
+// starts with prefix followed by DomainSep. The level of the longest prefix
+// that match the domain is used in place of the Slog Level, e.g. with the
+// level of "api.db" set to DebugPrio, the loggers "api.db" and "api.db.pool"
+// log debug entries while "api" and "api.dbx" still use their Level. The empty
+// prefix matches all domains.
+func SetDomainLevel(prefix string, level Level) {
+	domainsLck.Lock()
+	defer domainsLck.Unlock()
+	domains[prefix] = level
+	atomic.StoreInt32(&numDomains, int32(len(domains)))
+}
+
+// UnsetDomainLevel removes the level of prefix set by SetDomainLevel.
+func UnsetDomainLevel(prefix string) {
+	domainsLck.Lock()
+	defer domainsLck.Unlock()
+	delete(domains, prefix)
+	atomic.StoreInt32(&numDomains, int32(len(domains)))
+}
+
+// DomainLevels returns a copy of the levels set by SetDomainLevel.
+func DomainLevels() map[string]Level {
+	domainsLck.RLock()
+	defer domainsLck.RUnlock()
+	out := make(map[string]Level, len(domains))
+	for prefix, level := range domains {
+		out[prefix] = level
+	}
+	return out
+}
+
+// DomainLevel returns the level of the longest prefix of domain set by
+// SetDomainLevel.
+func DomainLevel(domain string) (Level, bool) {
+	return domainLevel([]byte(domain))
+}
+
+func domainLevel(domain []byte) (Level, bool) {
+	if atomic.LoadInt32(&numDomains) == 0 {
+		return 0, false
+	}
+	domainsLck.RLock()
+	defer domainsLck.RUnlock()
+	for i := len(domain); i >= 0; i-- {
+		if i > 0 && i < len(domain) && domain[i] != DomainSep {
+			continue
+		}
+		if level, ok := domains[string(domain[:i])]; ok {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// level returns the level that filters the entries of l: the level of its
+// tags, of its domain or the Slog Level.
+func (l *Slog) level() Level {
+	if level, ok := tagLevel(*l.Log.Tags); ok {
+		return level
+	}
+	if level, ok := domainLevel(l.Log.Domain); ok {
+		return level
+	}
+	return l.baseLevel()
+}
+
+// Sub returns a child logger with the domain of l followed by DomainSep and
+// name. The child inherits the formatter, committer, tags and fields of l and
+// shares its level: ChangeLevel in the child changes the level of l, of its
+// other children and of l's parents too. Use SetDomainLevel to change only
+// the level of the child and its own children.
+//
+//	db := logger.Sub("db")   // api.db
+//	pool := db.Sub("pool")   // api.db.pool
+func (l *Slog) Sub(name string) *Slog {
+	out := l.dup()
+	domain := make([]byte, 0, len(l.Log.Domain)+1+len(name))
+	domain = append(domain, l.Log.Domain...)
+	if len(domain) > 0 {
+		domain = append(domain, DomainSep)
+	}
+	domain = append(domain, name...)
+	out.Log.Domain = domain
+	out.Cp = false
+	return out
+}
+
+// Sub returns a child logger of the default logger.
+func Sub(name string) *Slog {
+	return log.Sub(name)
+}
@@ -0,0 +1,107 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func TestSub(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter: buf,
+		Level:   InfoPrio,
+	}
+	err := logger.Init("api", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	db := logger.Tag("tag1").Sub("db")
+	pool := db.Sub("pool")
+
+	db.Print(msg)
+	AssertLine(t, buf, "api.db - info - tag1 - benchmark log test")
+
+	pool.Error(msg)
+	AssertLine(t, buf, "api.db.pool - error - tag1 - benchmark log test")
+
+	logger.Print(msg)
+	AssertLine(t, buf, "api - info - benchmark log test")
+
+	pool.DebugLevel().Print(msg)
+	AssertEOF(t, buf)
+
+	// The level is shared with the parent and the siblings.
+	cache := logger.Sub("cache")
+	db.ChangeLevel(DebugPrio)
+	defer logger.ChangeLevel(InfoPrio)
+	logger.DebugLevel().Print(msg)
+	AssertLine(t, buf, "api - debug - benchmark log test")
+	cache.DebugLevel().Print(msg)
+	AssertLine(t, buf, "api.cache - debug - benchmark log test")
+	pool.DebugLevel().Print(msg)
+	AssertLine(t, buf, "api.db.pool - debug - tag1 - benchmark log test")
+}
+
+func TestDomainLevel(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter: buf,
+		Level:   InfoPrio,
+	}
+	err := logger.Init("api", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	SetDomainLevel("api.db", DebugPrio)
+	defer UnsetDomainLevel("api.db")
+
+	if level, ok := DomainLevel("api.db.pool"); !ok || level != DebugPrio {
+		t.Fatal("wrong domain level", level, ok)
+	}
+	if _, ok := DomainLevel("api.dbx"); ok {
+		t.Fatal("api.dbx has a level")
+	}
+	if len(DomainLevels()) != 1 {
+		t.Fatal("wrong number of domain levels", DomainLevels())
+	}
+
+	db := logger.Sub("db")
+	pool := db.Sub("pool")
+	dbx := logger.Sub("dbx")
+
+	db.DebugLevel().Print(msg)
+	AssertLine(t, buf, "api.db - debug - benchmark log test")
+
+	pool.DebugLevel().Print(msg)
+	AssertLine(t, buf, "api.db.pool - debug - benchmark log test")
+
+	if !pool.Enabled(DebugPrio) {
+		t.Fatal("debug isn't enabled for api.db.pool")
+	}
+
+	dbx.DebugLevel().Print(msg)
+	AssertEOF(t, buf)
+
+	logger.DebugLevel().Print(msg)
+	AssertEOF(t, buf)
+
+	SetDomainLevel("api.db.pool", ErrorPrio)
+	defer UnsetDomainLevel("api.db.pool")
+
+	pool.Warn(msg)
+	AssertEOF(t, buf)
+
+	db.DebugLevel().Print(msg)
+	AssertLine(t, buf, "api.db - debug - benchmark log test")
+}
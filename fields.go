@@ -0,0 +1,299 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// badKey is the key used when With receives a value without a key.
+const badKey = "!BADKEY"
+
+// Field is a key/value pair attached to the log entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+type fields []Field
+
+func newFields(length int) *fields {
+	f := make(fields, 0, length)
+	return &f
+}
+
+func (f *fields) copy() *fields {
+	if f == nil {
+		return nil
+	}
+	dst := make([]Field, len(*f))
+	copy(dst, *f)
+	fdst := fields(dst)
+	return &fdst
+}
+
+// copyTo copies f to dst reusing its memory.
+func (f *fields) copyTo(dst *fields) {
+	*dst = append((*dst)[:0], *f...)
+}
+
+// reset empties f keeping its memory.
+func (f *fields) reset() {
+	a := *f
+	for i := range a {
+		a[i] = Field{}
+	}
+	*f = a[:0]
+}
+
+func (f *fields) Add(fs ...Field) {
+	if f == nil {
+		return
+	}
+	*f = append(*f, fs...)
+}
+
+// AddPairs adds fields from a list of alternating keys and values. A Field in
+// the list is added as is.
+func (f *fields) AddPairs(keyvals ...interface{}) {
+	if f == nil {
+		return
+	}
+	for i := 0; i < len(keyvals); i++ {
+		switch k := keyvals[i].(type) {
+		case Field:
+			*f = append(*f, k)
+		case string:
+			if i+1 >= len(keyvals) {
+				*f = append(*f, Field{Key: badKey, Value: k})
+				continue
+			}
+			*f = append(*f, Field{Key: k, Value: keyvals[i+1]})
+			i++
+		default:
+			*f = append(*f, Field{Key: badKey, Value: k})
+		}
+	}
+}
+
+func (f *fields) Get(key string) (interface{}, bool) {
+	if f == nil {
+		return nil, false
+	}
+	a := *f
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i].Key == key {
+			return a[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+func (f *fields) Clean() {
+	if f == nil {
+		return
+	}
+	a := *f
+	a = a[:0]
+	*f = a
+}
+
+func (f fields) String() string {
+	if f == nil {
+		return ""
+	}
+	buf := make([]byte, 0, 64)
+	f.encodeText(&buf)
+	return string(buf)
+}
+
+// encodeText appends the fields in the form key=value, each one followed by a
+// space.
+func (f fields) encodeText(buf *[]byte) {
+	for i := 0; i < len(f); i++ {
+		*buf = append(*buf, f[i].Key...)
+		*buf = append(*buf, '=')
+		appendTextValue(buf, f[i].Value)
+		*buf = append(*buf, ' ')
+	}
+}
+
+// EncodeJSON appends the fields as members of a json object, each one
+// preceded by a comma. The keys of the members written by the JSON formatter,
+// like Message, are prefixed with "fields." to not duplicate them.
+func (f fields) EncodeJSON(buf *[]byte) {
+	for i := 0; i < len(f); i++ {
+		*buf = append(*buf, ',')
+		if jsonReserved(f[i].Key) {
+			appendJSONValue(buf, "fields."+f[i].Key)
+		} else {
+			appendJSONValue(buf, f[i].Key)
+		}
+		*buf = append(*buf, ':')
+		appendJSONValue(buf, f[i].Value)
+	}
+}
+
+func needsQuote(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			return true
+		}
+	}
+	return false
+}
+
+func appendTextString(buf *[]byte, s string) {
+	if needsQuote(s) {
+		*buf = strconv.AppendQuote(*buf, s)
+		return
+	}
+	*buf = append(*buf, s...)
+}
+
+func appendTextValue(buf *[]byte, v interface{}) {
+	switch val := v.(type) {
+	case nil:
+		*buf = append(*buf, "<nil>"...)
+	case string:
+		appendTextString(buf, val)
+	case []byte:
+		appendTextString(buf, string(val))
+	case bool:
+		*buf = strconv.AppendBool(*buf, val)
+	case int:
+		*buf = strconv.AppendInt(*buf, int64(val), 10)
+	case int8:
+		*buf = strconv.AppendInt(*buf, int64(val), 10)
+	case int16:
+		*buf = strconv.AppendInt(*buf, int64(val), 10)
+	case int32:
+		*buf = strconv.AppendInt(*buf, int64(val), 10)
+	case int64:
+		*buf = strconv.AppendInt(*buf, val, 10)
+	case uint:
+		*buf = strconv.AppendUint(*buf, uint64(val), 10)
+	case uint8:
+		*buf = strconv.AppendUint(*buf, uint64(val), 10)
+	case uint16:
+		*buf = strconv.AppendUint(*buf, uint64(val), 10)
+	case uint32:
+		*buf = strconv.AppendUint(*buf, uint64(val), 10)
+	case uint64:
+		*buf = strconv.AppendUint(*buf, val, 10)
+	case float32:
+		*buf = strconv.AppendFloat(*buf, float64(val), 'g', -1, 32)
+	case float64:
+		*buf = strconv.AppendFloat(*buf, val, 'g', -1, 64)
+	case error:
+		appendTextString(buf, val.Error())
+	case fmt.Stringer:
+		appendTextString(buf, val.String())
+	default:
+		appendTextString(buf, fmt.Sprint(val))
+	}
+}
+
+func appendJSONFloat(buf *[]byte, f float64, bitSize int) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		*buf = append(*buf, '"')
+		*buf = strconv.AppendFloat(*buf, f, 'g', -1, bitSize)
+		*buf = append(*buf, '"')
+		return
+	}
+	*buf = strconv.AppendFloat(*buf, f, 'g', -1, bitSize)
+}
+
+func appendJSONValue(buf *[]byte, v interface{}) {
+	switch val := v.(type) {
+	case nil:
+		*buf = append(*buf, "null"...)
+	case string:
+		appendJSONString(buf, val)
+	case []byte:
+		appendJSONString(buf, string(val))
+	case bool:
+		*buf = strconv.AppendBool(*buf, val)
+	case int:
+		*buf = strconv.AppendInt(*buf, int64(val), 10)
+	case int8:
+		*buf = strconv.AppendInt(*buf, int64(val), 10)
+	case int16:
+		*buf = strconv.AppendInt(*buf, int64(val), 10)
+	case int32:
+		*buf = strconv.AppendInt(*buf, int64(val), 10)
+	case int64:
+		*buf = strconv.AppendInt(*buf, val, 10)
+	case uint:
+		*buf = strconv.AppendUint(*buf, uint64(val), 10)
+	case uint8:
+		*buf = strconv.AppendUint(*buf, uint64(val), 10)
+	case uint16:
+		*buf = strconv.AppendUint(*buf, uint64(val), 10)
+	case uint32:
+		*buf = strconv.AppendUint(*buf, uint64(val), 10)
+	case uint64:
+		*buf = strconv.AppendUint(*buf, val, 10)
+	case float32:
+		appendJSONFloat(buf, float64(val), 32)
+	case float64:
+		appendJSONFloat(buf, val, 64)
+	case json.Marshaler:
+		b, err := val.MarshalJSON()
+		if err != nil {
+			appendJSONString(buf, err.Error())
+			return
+		}
+		*buf = append(*buf, b...)
+	case error:
+		appendJSONString(buf, val.Error())
+	case fmt.Stringer:
+		appendJSONString(buf, val.String())
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			appendJSONString(buf, fmt.Sprint(val))
+			return
+		}
+		*buf = append(*buf, b...)
+	}
+}
+
+// jsonReserved returns true if key is a member written by the JSON formatter.
+func jsonReserved(key string) bool {
+	switch key {
+	case "Domain", "Priority", "Timestamp", "Tags", "Message", "File":
+		return true
+	}
+	return false
+}
+
+// journalField converts a field key to a valid journal field name: uppercase
+// letters, numbers and underscores, not starting with an underscore.
+func journalField(key string) string {
+	b := make([]byte, 0, len(key))
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			b = append(b, c-'a'+'A')
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			b = append(b, c)
+		default:
+			b = append(b, '_')
+		}
+	}
+	for len(b) > 0 && (b[0] == '_' || (b[0] >= '0' && b[0] <= '9')) {
+		b = b[1:]
+	}
+	return string(b)
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func TestWith(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter: buf,
+		Level:   DebugPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	logger.With("user", "john", "id", 42).Println(msg)
+	AssertLine(t, buf, "teste - info - user=john id=42 - benchmark log test")
+
+	logger.With("path", "/a b").Fields(Field{Key: "ok", Value: true}).Println(msg)
+	AssertLine(t, buf, "teste - info - path=\"/a b\" ok=true - benchmark log test")
+
+	logger.With("odd").Println(msg)
+	AssertLine(t, buf, "teste - info - !BADKEY=odd - benchmark log test")
+
+	logger.Println(msg)
+	AssertLine(t, buf, "teste - info - benchmark log test")
+
+	def := logger.With("service", "api").MakeDefault()
+	def.With("id", 1).Println(msg)
+	AssertLine(t, buf, "teste - info - service=api id=1 - benchmark log test")
+	def.Println(msg)
+	AssertLine(t, buf, "teste - info - service=api - benchmark log test")
+}
+
+func TestWithJSON(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter:   buf,
+		Formatter: JSON,
+		Level:     DebugPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	logger.With("user", "john", "id", 42, "ratio", 0.5, "ok", true, "nothing", nil).Println(msg)
+	v := make(map[string]interface{})
+	err = json.Unmarshal(buf.Bytes(), &v)
+	if err != nil {
+		t.Fatal(err, buf.String())
+	}
+	buf.Reset()
+	if v["user"] != "john" || v["id"] != float64(42) || v["ratio"] != 0.5 || v["ok"] != true || v["nothing"] != nil {
+		t.Fatalf("wrong fields: %v", v)
+	}
+	if v["Message"] != msg || v["Domain"] != "teste" {
+		t.Fatalf("wrong entry: %v", v)
+	}
+}
+
+func TestFreeWith(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+	err := SetOutput("teste", ProtoPrio, buf, nil, nil, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	With("a", 1).Print(msg)
+	AssertLine(t, buf, "teste - info - a=1 - benchmark log test")
+	Fields(Field{Key: "b", Value: "c"}).ErrorLevel().Print(msg)
+	AssertLine(t, buf, "teste - error - b=c - benchmark log test")
+}
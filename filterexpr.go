@@ -0,0 +1,552 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"regexp"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/fcavani/e"
+)
+
+// CompileFilter compiles a filter expression into a function that can be used
+// as the Slog Filter. The expression is a combination, with || (or), && (and),
+// ! (not) and parenthesis, of the conditions:
+//
+//	level OP LEVEL      the priority, OP is ==, !=, <, <=, > or >=
+//	tag:NAME            the entry has the tag
+//	tag OP VALUE        some tag is or matches VALUE, OP is ==, !=, ~ or !~
+//	domain OP VALUE     the domain is or matches VALUE
+//	msg OP VALUE        the message is or matches VALUE
+//	field:KEY           the entry has the field
+//	field:KEY OP VALUE  the field value is, matches or, with <, <=, > and >=,
+//	                    is numerically compared with VALUE
+//	true, false
+//
+// ~ is true if the regular expression VALUE matches some part of the text and
+// !~ if it doesn't. LEVEL is parsed by ParseLevel. Names and values are words
+// of letters, digits, '_', '-', '.' and '*' or strings quoted like in Go. &&
+// has precedence over ||:
+//
+//	level>=error || (tag:db && domain~"^api\\.") && !msg~"healthcheck"
+//
+// The empty expression is true.
+func CompileFilter(expr string) (func(l *Slog) bool, error) {
+	p := &filterParser{expr: expr}
+	p.next()
+	if p.tok.kind == tokEOF {
+		return func(_ *Slog) bool { return true }, nil
+	}
+	fn := p.or()
+	if p.err == nil && p.tok.kind != tokEOF {
+		p.fail("unexpected " + p.tok.String())
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return fn, nil
+}
+
+// FilterExpr is a filter compiled from an expression that can be replaced
+// while the loggers are running. The zero value passes all entries. Use the
+// Filter method as the Slog Filter:
+//
+//	filter := new(slog.FilterExpr)
+//	err := filter.Set(`level>=warning || tag:audit`)
+//	logger := &slog.Slog{Filter: filter.Filter}
+type FilterExpr struct {
+	v atomic.Value
+}
+
+type compiledFilter struct {
+	expr string
+	fn   func(l *Slog) bool
+}
+
+// NewFilterExpr returns a FilterExpr with expr compiled.
+func NewFilterExpr(expr string) (*FilterExpr, error) {
+	f := new(FilterExpr)
+	err := f.Set(expr)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Set compiles expr and, if it is valid, replaces the current expression.
+func (f *FilterExpr) Set(expr string) error {
+	fn, err := CompileFilter(expr)
+	if err != nil {
+		return err
+	}
+	f.v.Store(&compiledFilter{expr: expr, fn: fn})
+	return nil
+}
+
+// String returns the current expression.
+func (f *FilterExpr) String() string {
+	c, _ := f.v.Load().(*compiledFilter)
+	if c == nil {
+		return ""
+	}
+	return c.expr
+}
+
+// Filter returns true if the entry satisfies the current expression.
+func (f *FilterExpr) Filter(l *Slog) bool {
+	c, _ := f.v.Load().(*compiledFilter)
+	if c == nil {
+		return true
+	}
+	return c.fn(l)
+}
+
+type tokKind uint8
+
+const (
+	tokEOF tokKind = iota
+	tokWord
+	tokString
+	tokOr
+	tokAnd
+	tokNot
+	tokLParen
+	tokRParen
+	tokColon
+	tokEq
+	tokNe
+	tokLt
+	tokLe
+	tokGt
+	tokGe
+	tokMatch
+	tokNotMatch
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return "string " + t.text
+	case tokWord:
+		return strconv.Quote(t.text)
+	default:
+		return "'" + t.text + "'"
+	}
+}
+
+type filterParser struct {
+	expr string
+	pos  int
+	tok  token
+	err  error
+}
+
+// fail records the first error, the position is the column of the current
+// token.
+func (p *filterParser) fail(msg string) {
+	if p.err != nil {
+		return
+	}
+	p.err = e.New("invalid filter: %v at column %v in %q", msg, p.tok.pos+1, p.expr)
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '-' || c == '.' || c == '*'
+}
+
+var operators = []struct {
+	text string
+	kind tokKind
+}{
+	{"||", tokOr}, {"&&", tokAnd}, {"==", tokEq}, {"!=", tokNe},
+	{"!~", tokNotMatch}, {"<=", tokLe}, {">=", tokGe}, {"!", tokNot},
+	{"~", tokMatch}, {"<", tokLt}, {">", tokGt}, {"(", tokLParen},
+	{")", tokRParen}, {":", tokColon},
+}
+
+// next reads the next token.
+func (p *filterParser) next() {
+	for p.pos < len(p.expr) && (p.expr[p.pos] == ' ' || p.expr[p.pos] == '\t' || p.expr[p.pos] == '\n' || p.expr[p.pos] == '\r') {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.expr) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+	c := p.expr[p.pos]
+	switch {
+	case isWordByte(c):
+		for p.pos < len(p.expr) && isWordByte(p.expr[p.pos]) {
+			p.pos++
+		}
+		p.tok = token{kind: tokWord, text: p.expr[start:p.pos], pos: start}
+		return
+	case c == '"' || c == '`':
+		p.pos++
+		for p.pos < len(p.expr) && p.expr[p.pos] != c {
+			if c == '"' && p.expr[p.pos] == '\\' {
+				p.pos++
+			}
+			p.pos++
+		}
+		if p.pos >= len(p.expr) {
+			p.tok = token{kind: tokEOF, pos: start}
+			p.fail("unterminated string")
+			return
+		}
+		p.pos++
+		p.tok = token{kind: tokString, text: p.expr[start:p.pos], pos: start}
+		return
+	}
+	for _, op := range operators {
+		if len(p.expr)-p.pos >= len(op.text) && p.expr[p.pos:p.pos+len(op.text)] == op.text {
+			p.pos += len(op.text)
+			p.tok = token{kind: op.kind, text: op.text, pos: start}
+			return
+		}
+	}
+	p.tok = token{kind: tokEOF, text: string(c), pos: start}
+	p.fail("unexpected character " + strconv.QuoteRune(rune(c)))
+}
+
+func (p *filterParser) or() func(l *Slog) bool {
+	left := p.and()
+	for p.err == nil && p.tok.kind == tokOr {
+		p.next()
+		a, b := left, p.and()
+		left = func(l *Slog) bool { return a(l) || b(l) }
+	}
+	return left
+}
+
+func (p *filterParser) and() func(l *Slog) bool {
+	left := p.unary()
+	for p.err == nil && p.tok.kind == tokAnd {
+		p.next()
+		a, b := left, p.unary()
+		left = func(l *Slog) bool { return a(l) && b(l) }
+	}
+	return left
+}
+
+func (p *filterParser) unary() func(l *Slog) bool {
+	if p.err != nil {
+		return nil
+	}
+	switch p.tok.kind {
+	case tokNot:
+		p.next()
+		fn := p.unary()
+		return func(l *Slog) bool { return !fn(l) }
+	case tokLParen:
+		p.next()
+		fn := p.or()
+		if p.err == nil && p.tok.kind != tokRParen {
+			p.fail("expected ')' but found " + p.tok.String())
+		}
+		p.next()
+		return fn
+	case tokWord:
+		return p.condition()
+	default:
+		p.fail("expected a condition but found " + p.tok.String())
+		return nil
+	}
+}
+
+// value reads a word or a quoted string.
+func (p *filterParser) value(what string) (string, bool) {
+	if p.err != nil {
+		return "", false
+	}
+	switch p.tok.kind {
+	case tokWord:
+		v := p.tok.text
+		p.next()
+		return v, true
+	case tokString:
+		v, err := strconv.Unquote(p.tok.text)
+		if err != nil {
+			p.fail("invalid string " + p.tok.text)
+			return "", false
+		}
+		p.next()
+		return v, true
+	default:
+		p.fail("expected " + what + " but found " + p.tok.String())
+		return "", false
+	}
+}
+
+func (p *filterParser) condition() func(l *Slog) bool {
+	name := p.tok
+	p.next()
+	switch name.text {
+	case "true":
+		return func(_ *Slog) bool { return true }
+	case "false":
+		return func(_ *Slog) bool { return false }
+	case "level":
+		return p.level()
+	case "tag":
+		if p.tok.kind == tokColon {
+			p.next()
+			tag, _ := p.value("a tag")
+			return func(l *Slog) bool { return l.Log.Tags.Have(tag) }
+		}
+		m := p.match()
+		if m == nil {
+			return nil
+		}
+		return func(l *Slog) bool {
+			for _, tag := range *l.Log.Tags {
+				if m.matchString(tag) {
+					return true
+				}
+			}
+			return false
+		}
+	case "domain":
+		m := p.match()
+		if m == nil {
+			return nil
+		}
+		return func(l *Slog) bool { return m.matchBytes(l.Log.Domain) }
+	case "msg":
+		m := p.match()
+		if m == nil {
+			return nil
+		}
+		return func(l *Slog) bool { return m.matchString(l.Log.msg) }
+	case "field":
+		return p.field()
+	default:
+		p.tok = name
+		p.fail("unknown condition " + name.String())
+		return nil
+	}
+}
+
+func (p *filterParser) level() func(l *Slog) bool {
+	op := p.tok
+	switch op.kind {
+	case tokEq, tokNe, tokLt, tokLe, tokGt, tokGe:
+	default:
+		p.fail("expected a comparison after level but found " + op.String())
+		return nil
+	}
+	p.next()
+	valuePos := p.tok.pos
+	str, ok := p.value("a level")
+	if !ok {
+		return nil
+	}
+	level, err := ParseLevel(str)
+	if err != nil {
+		p.tok = token{pos: valuePos}
+		p.fail("invalid level " + strconv.Quote(str))
+		return nil
+	}
+	switch op.kind {
+	case tokEq:
+		return func(l *Slog) bool { return l.Log.Priority == level }
+	case tokNe:
+		return func(l *Slog) bool { return l.Log.Priority != level }
+	case tokLt:
+		return func(l *Slog) bool { return l.Log.Priority < level }
+	case tokLe:
+		return func(l *Slog) bool { return l.Log.Priority <= level }
+	case tokGt:
+		return func(l *Slog) bool { return l.Log.Priority > level }
+	default:
+		return func(l *Slog) bool { return l.Log.Priority >= level }
+	}
+}
+
+// filterMatch compares a text with ==, !=, ~ or !~.
+type filterMatch struct {
+	op    tokKind
+	value string
+	re    *regexp.Regexp
+}
+
+func (m *filterMatch) matchString(s string) bool {
+	switch m.op {
+	case tokEq:
+		return s == m.value
+	case tokNe:
+		return s != m.value
+	case tokMatch:
+		return m.re.MatchString(s)
+	default:
+		return !m.re.MatchString(s)
+	}
+}
+
+func (m *filterMatch) matchBytes(b []byte) bool {
+	switch m.op {
+	case tokEq:
+		return string(b) == m.value
+	case tokNe:
+		return string(b) != m.value
+	case tokMatch:
+		return m.re.Match(b)
+	default:
+		return !m.re.Match(b)
+	}
+}
+
+// match reads a text operator and its value.
+func (p *filterParser) match() *filterMatch {
+	if p.err != nil {
+		return nil
+	}
+	op := p.tok
+	switch op.kind {
+	case tokEq, tokNe, tokMatch, tokNotMatch:
+	default:
+		p.fail("expected ==, !=, ~ or !~ but found " + op.String())
+		return nil
+	}
+	p.next()
+	valuePos := p.tok.pos
+	v, ok := p.value("a value")
+	if !ok {
+		return nil
+	}
+	m := &filterMatch{op: op.kind, value: v}
+	if op.kind == tokMatch || op.kind == tokNotMatch {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			p.tok = token{pos: valuePos}
+			p.fail("invalid regular expression: " + err.Error())
+			return nil
+		}
+		m.re = re
+	}
+	return m
+}
+
+func (p *filterParser) field() func(l *Slog) bool {
+	if p.tok.kind != tokColon {
+		p.fail("expected ':' after field but found " + p.tok.String())
+		return nil
+	}
+	p.next()
+	key, ok := p.value("a field key")
+	if !ok {
+		return nil
+	}
+	switch p.tok.kind {
+	case tokLt, tokLe, tokGt, tokGe:
+		return p.fieldNumber(key)
+	case tokEq, tokNe, tokMatch, tokNotMatch:
+		m := p.match()
+		if m == nil {
+			return nil
+		}
+		return func(l *Slog) bool {
+			v, ok := l.Log.Fields.Get(key)
+			return ok && m.matchString(fieldText(v))
+		}
+	}
+	return func(l *Slog) bool {
+		_, ok := l.Log.Fields.Get(key)
+		return ok
+	}
+}
+
+func (p *filterParser) fieldNumber(key string) func(l *Slog) bool {
+	op := p.tok.kind
+	p.next()
+	valuePos := p.tok.pos
+	str, ok := p.value("a number")
+	if !ok {
+		return nil
+	}
+	n, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		p.tok = token{pos: valuePos}
+		p.fail("invalid number " + strconv.Quote(str))
+		return nil
+	}
+	return func(l *Slog) bool {
+		v, ok := l.Log.Fields.Get(key)
+		if !ok {
+			return false
+		}
+		f, ok := fieldNumber(v)
+		if !ok {
+			return false
+		}
+		switch op {
+		case tokLt:
+			return f < n
+		case tokLe:
+			return f <= n
+		case tokGt:
+			return f > n
+		default:
+			return f >= n
+		}
+	}
+}
+
+// fieldText returns the value of a field as text, strings aren't quoted.
+func fieldText(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	}
+	buf := make([]byte, 0, 32)
+	appendTextValue(&buf, v)
+	return string(buf)
+}
+
+func fieldNumber(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case int:
+		return float64(val), true
+	case int8:
+		return float64(val), true
+	case int16:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case uint:
+		return float64(val), true
+	case uint8:
+		return float64(val), true
+	case uint16:
+		return float64(val), true
+	case uint32:
+		return float64(val), true
+	case uint64:
+		return float64(val), true
+	case float32:
+		return float64(val), true
+	case float64:
+		return val, true
+	case string:
+		f, err := strconv.ParseFloat(val, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
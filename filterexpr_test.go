@@ -0,0 +1,166 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/fcavani/slog"
+)
+
+func TestCompileFilter(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+	filter := new(FilterExpr)
+	logger := &Slog{
+		Level:   DebugPrio,
+		Writter: buf,
+		Filter:  filter.Filter,
+	}
+	err := logger.Init("api", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := logger.Sub("users")
+	other := &Slog{
+		Level:   DebugPrio,
+		Writter: buf,
+		Filter:  filter.Filter,
+	}
+	err = other.Init("worker", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		log  func()
+		pass bool
+	}{
+		{"", func() { logger.DebugLevel().Print(msg) }, true},
+		{"level>=error", func() { logger.Warn(msg) }, false},
+		{"level>=error", func() { logger.Error(msg) }, true},
+		{"level == debug", func() { logger.DebugLevel().Print(msg) }, true},
+		{"level<info", func() { logger.Print(msg) }, false},
+		{"tag:db", func() { logger.Tag("db", "x").Print(msg) }, true},
+		{"tag:db", func() { logger.Tag("dbx").Print(msg) }, false},
+		{`tag~"^cache"`, func() { logger.Tag("x", "cache-1").Print(msg) }, true},
+		{`domain=="api.users"`, func() { api.Print(msg) }, true},
+		{`domain~"^api\\."`, func() { logger.Print(msg) }, false},
+		{`domain!~"^api"`, func() { other.Print(msg) }, true},
+		{`msg~"health"`, func() { logger.Print("GET /healthcheck") }, true},
+		{`!msg~"health"`, func() { logger.Print("GET /healthcheck") }, false},
+		{`msg != "a b"`, func() { logger.Print("a b") }, false},
+		{"field:user", func() { logger.With("user", 1).Print(msg) }, true},
+		{"field:user", func() { logger.Print(msg) }, false},
+		{"field:user==john", func() { logger.With("user", "john").Print(msg) }, true},
+		{"field:status == 500", func() { logger.With("status", 500).Print(msg) }, true},
+		{"field:latency>1.5", func() { logger.With("latency", 2).Print(msg) }, true},
+		{"field:latency>1.5", func() { logger.With("latency", "1").Print(msg) }, false},
+		{"field:latency<=10", func() { logger.With("latency", "fast").Print(msg) }, false},
+		{"true && !false", func() { logger.Print(msg) }, true},
+		{
+			`level>=error || (tag:db && domain~"^api") && !msg~"healthcheck"`,
+			func() { api.Tag("db").Print("query") },
+			true,
+		},
+		{
+			`level>=error || (tag:db && domain~"^api") && !msg~"healthcheck"`,
+			func() { api.Tag("db").Print("healthcheck") },
+			false,
+		},
+		{
+			`level>=error || (tag:db && domain~"^api") && !msg~"healthcheck"`,
+			func() { other.Error("healthcheck") },
+			true,
+		},
+		{
+			`(level>=error || tag:db) && domain~"^api"`,
+			func() { other.Error(msg) },
+			false,
+		},
+	}
+	for i, test := range tests {
+		err := filter.Set(test.expr)
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if filter.String() != test.expr {
+			t.Fatal(i, "wrong expression", filter.String())
+		}
+		buf.Reset()
+		test.log()
+		if pass := buf.Len() > 0; pass != test.pass {
+			t.Fatalf("%v: %q expected %v got %v", i, test.expr, test.pass, pass)
+		}
+	}
+}
+
+func TestCompileFilterErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{"level", "expected a comparison after level but found end of expression at column 6"},
+		{"level>=", "expected a level but found end of expression at column 8"},
+		{"level>=bogus", `invalid level "bogus" at column 8`},
+		{"level ~ error", `expected a comparison after level but found '~' at column 7`},
+		{"tag:", "expected a tag but found end of expression at column 5"},
+		{"tag db", `expected ==, !=, ~ or !~ but found "db" at column 5`},
+		{"msg~\"abc", "unterminated string at column 5"},
+		{`msg~"("`, "invalid regular expression: error parsing regexp: missing closing ): `(` at column 5"},
+		{"field:x > abc", `invalid number "abc" at column 11`},
+		{"field x", `expected ':' after field but found "x" at column 7`},
+		{"(level>=error", "expected ')' but found end of expression at column 14"},
+		{"level>=error)", "unexpected ')' at column 13"},
+		{"level>=error tag:db", `unexpected "tag" at column 14`},
+		{"level>=error ||", "expected a condition but found end of expression at column 16"},
+		{"&& tag:db", "expected a condition but found '&&' at column 1"},
+		{"host == x", `unknown condition "host" at column 1`},
+		{"tag:db & tag:x", "unexpected character '&' at column 8"},
+	}
+	for _, test := range tests {
+		_, err := CompileFilter(test.expr)
+		if err == nil {
+			t.Fatalf("%q: expected error", test.expr)
+		}
+		if !strings.Contains(err.Error(), "invalid filter: "+test.err+" in ") {
+			t.Fatalf("%q: wrong error %q", test.expr, err)
+		}
+	}
+
+	filter, err := NewFilterExpr("tag:db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = filter.Set("tag:")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if filter.String() != "tag:db" {
+		t.Fatal("filter replaced by invalid expression", filter.String())
+	}
+}
+
+func BenchmarkFilterExpr(b *testing.B) {
+	filter, err := NewFilterExpr(`level>=error || (tag:db && domain~"^api") && !msg~"healthcheck"`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	logger := &Slog{
+		Level:   DebugPrio,
+		Writter: &writerCloser{bytes.NewBuffer([]byte{})},
+		Filter:  filter.Filter,
+	}
+	err = logger.Init("api", 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Tag("db").Print("healthcheck")
+	}
+}
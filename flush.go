@@ -0,0 +1,163 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fcavani/e"
+)
+
+// Flusher is implemented by committers and writers that buffer the entries,
+// Flush returns when all buffered entries are written.
+type Flusher interface {
+	Flush() error
+}
+
+// Syncer is implemented by writers that can commit their content to a stable
+// storage, like os.File.
+type Syncer interface {
+	Sync() error
+}
+
+// DefaultFlushTimeout is the time Flush waits if Slog.FlushTimeout is zero.
+var DefaultFlushTimeout = 5 * time.Second
+
+// ErrFlushTimeout is returned by Flush if the committer or the writer takes
+// more than the timeout to flush.
+const ErrFlushTimeout = "flush timeout"
+
+var hooksLck sync.Mutex
+var hooks []func()
+
+// OnExit registers a function that will be called before Fatal, Panic and
+// GoPanic exit or panic. The functions are called in the order that they were
+// registered.
+func OnExit(fn func()) {
+	hooksLck.Lock()
+	defer hooksLck.Unlock()
+	hooks = append(hooks, fn)
+}
+
+func runHooks() {
+	hooksLck.Lock()
+	hs := make([]func(), len(hooks))
+	copy(hs, hooks)
+	hooksLck.Unlock()
+	for _, fn := range hs {
+		fn()
+	}
+}
+
+// ignoreSyncErr returns true for the errors returned by Sync when the file
+// doesn't support it, like the os.Stdout in a terminal or a pipe.
+func ignoreSyncErr(err error) bool {
+	if pe, ok := err.(*os.PathError); ok {
+		err = pe.Err
+	}
+	return err == syscall.EINVAL || err == syscall.ENOTSUP || err == syscall.ENOTTY
+}
+
+func flush(flusher Flusher, writter io.Writer) error {
+	if flusher != nil {
+		err := flusher.Flush()
+		if err != nil {
+			return e.Forward(err)
+		}
+	}
+	switch w := writter.(type) {
+	case Flusher:
+		err := w.Flush()
+		if err != nil {
+			return e.Forward(err)
+		}
+	case Syncer:
+		err := w.Sync()
+		if err != nil && !ignoreSyncErr(err) {
+			return e.Forward(err)
+		}
+	}
+	return nil
+}
+
+// Flush waits until the committer, if Flusher is set, and the writer have
+// written all the entries. If the writer isn't a Flusher but is a Syncer,
+// Sync is called. Flush gives up after FlushTimeout.
+func (l *Slog) Flush() error {
+	return flushTimeout(l.Flusher, l.Writter, l.FlushTimeout)
+}
+
+func flushTimeout(flusher Flusher, writter io.Writer, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultFlushTimeout
+	}
+	ch := make(chan error, 1)
+	go func() {
+		ch <- flush(flusher, writter)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-ch:
+		return err
+	case <-timer.C:
+		return e.New(ErrFlushTimeout)
+	}
+}
+
+// ending is what Fatal, Panic and GoPanic need after the entry is committed.
+// It's saved before the commit because the commit puts the logger back in the
+// pool, where it can be taken and changed by other goroutine.
+type ending struct {
+	flusher Flusher
+	writter io.WriteCloser
+	timeout time.Duration
+	cfg     *config
+	exiter  func(int)
+}
+
+func (l *Slog) ending() *ending {
+	return &ending{
+		flusher: l.Flusher,
+		writter: l.Writter,
+		timeout: l.FlushTimeout,
+		cfg:     l.cfg,
+		exiter:  l.Exiter,
+	}
+}
+
+// shutdown flushes the logger, runs the exit hooks and closes the writer.
+func (end *ending) shutdown() {
+	err := flushTimeout(end.flusher, end.writter, end.timeout)
+	if err != nil {
+		println("SLOG flush failed:", err.Error())
+	}
+	runHooks()
+	if end.writter == nil {
+		return
+	}
+	err = end.writter.Close()
+	if err != nil {
+		println("SLOG close failed:", err.Error())
+	}
+}
+
+// exit calls the exiter of the logger.
+func (end *ending) exit(code int) {
+	if end.cfg == nil {
+		end.exiter(code)
+		return
+	}
+	end.cfg.getExiter()(code)
+}
+
+// Flush flushes the default logger.
+func Flush() error {
+	return log.Flush()
+}
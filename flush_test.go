@@ -0,0 +1,113 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+type flushWriter struct {
+	writerCloser
+	events *[]string
+	delay  time.Duration
+}
+
+func (f *flushWriter) Flush() error {
+	time.Sleep(f.delay)
+	*f.events = append(*f.events, "flush")
+	return nil
+}
+
+func (f *flushWriter) Close() error {
+	*f.events = append(*f.events, "close")
+	return nil
+}
+
+func TestFlushBeforeExit(t *testing.T) {
+	var events []string
+	w := &flushWriter{
+		writerCloser: writerCloser{bytes.NewBuffer([]byte{})},
+		events:       &events,
+	}
+	async := &Async{Size: 16}
+	defer async.Close()
+
+	logger := &Slog{
+		Writter: w,
+		Level:   DebugPrio,
+		Commit:  async.Commit,
+		Flusher: async,
+		Exiter: func(int) {
+			events = append(events, "exit")
+		},
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	OnExit(func() {
+		events = append(events, "hook1")
+	})
+	OnExit(func() {
+		events = append(events, "hook2")
+	})
+
+	for i := 0; i < 10; i++ {
+		logger.Print(msg)
+	}
+	logger.Fatal(msg)
+
+	if n := strings.Count(w.String(), "\n"); n != 11 {
+		t.Fatal("entries lost", n)
+	}
+	if s := strings.Join(events, ","); !strings.HasPrefix(s, "flush,hook1,hook2") || !strings.HasSuffix(s, "close,exit") {
+		t.Fatal("wrong order", s)
+	}
+}
+
+func TestFlushTimeout(t *testing.T) {
+	var events []string
+	w := &flushWriter{
+		writerCloser: writerCloser{bytes.NewBuffer([]byte{})},
+		events:       &events,
+		delay:        100 * time.Millisecond,
+	}
+
+	logger := &Slog{
+		Writter:      w,
+		Level:        DebugPrio,
+		FlushTimeout: 10 * time.Millisecond,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	err = logger.Flush()
+	if !e.Equal(err, ErrFlushTimeout) {
+		t.Fatal("expected timeout, got", err)
+	}
+}
+
+func TestFlushStdout(t *testing.T) {
+	logger := &Slog{
+		Level: DebugPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	err = logger.Flush()
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package slog
+
+import (
+	"context"
+	stdslog "log/slog"
+	"runtime"
+)
+
+// TagsKey is the key of the log/slog attributes that are converted to tags
+// by Handler. The value can be a string or a []string.
+const TagsKey = "tags"
+
+// FromStdLevel converts a log/slog level to Level. Levels below
+// log/slog.LevelDebug are ProtoPrio and levels above log/slog.LevelError are
+// ErrorPrio.
+func FromStdLevel(level stdslog.Level) Level {
+	switch {
+	case level < stdslog.LevelDebug:
+		return ProtoPrio
+	case level < stdslog.LevelInfo:
+		return DebugPrio
+	case level < stdslog.LevelWarn:
+		return InfoPrio
+	case level < stdslog.LevelError:
+		return WarnPrio
+	default:
+		return ErrorPrio
+	}
+}
+
+// StdLevel converts a Level to the log/slog level.
+func StdLevel(level Level) stdslog.Level {
+	switch level {
+	case ProtoPrio:
+		return stdslog.LevelDebug - 4
+	case DebugPrio:
+		return stdslog.LevelDebug
+	case InfoPrio:
+		return stdslog.LevelInfo
+	case WarnPrio:
+		return stdslog.LevelWarn
+	case ErrorPrio:
+		return stdslog.LevelError
+	default:
+		return stdslog.LevelError + 4
+	}
+}
+
+// Handler is a log/slog Handler that sends the records to a *Slog. The
+// attributes are converted to fields, the keys of attributes inside groups are
+// prefixed with the group names separated by dots, and the attributes with
+// the key TagsKey become tags.
+//
+//	logger := stdslog.New(slog.NewHandler(slog.DefaultLogger()))
+type Handler struct {
+	logger *Slog
+	fields []Field
+	tags   []string
+	prefix string
+}
+
+// NewHandler returns a log/slog Handler backed by l. If l is a copy, like
+// the one returned by Tag, it's turned in a logger with MakeDefault.
+func NewHandler(l *Slog) *Handler {
+	if l.Cp {
+		l = l.MakeDefault()
+	}
+	return &Handler{
+		logger: l,
+	}
+}
+
+// Enabled reports whether the level pass the level of the Slog, with the tags
+// added by WithAttrs.
+func (h *Handler) Enabled(_ context.Context, level stdslog.Level) bool {
+	return FromStdLevel(level) >= h.level()
+}
+
+// level is like Slog.level but also considers the tags of h.
+func (h *Handler) level() Level {
+	if level, ok := tagLevel(tags(h.tags)); ok {
+		if l, ok := tagLevel(*h.logger.Log.Tags); ok && l < level {
+			return l
+		}
+		return level
+	}
+	return h.logger.level()
+}
+
+// Handle commits the record with its time, or with the time of the commit if
+// it is zero. The fields extracted from ctx are added like Ctx does.
+func (h *Handler) Handle(ctx context.Context, r stdslog.Record) error {
+	l := h.logger.copy()
+	l.Log.Priority = FromStdLevel(r.Level)
+	l.Log.Timestamp = r.Time
+	l.Log.Tags.Add(h.tags...)
+	l.Log.Fields.Add(h.fields...)
+	l.Log.Fields.addContext(ctx)
+	r.Attrs(func(a stdslog.Attr) bool {
+		addAttr(l.Log.Fields, l.Log.Tags, h.prefix, a)
+		return true
+	})
+	if l.Log.DoDi && r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := frames.Next()
+		l.Log.file = fileLine(f.File, f.Line)
+	}
+	l.Log.Message(r.Message)
+	l.commit()
+	return nil
+}
+
+// addAttr adds the attribute to the fields or to the tags. The keys inside
+// groups are prefixed with the group name.
+func addAttr(fs *fields, ts *tags, prefix string, a stdslog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(stdslog.Attr{}) {
+		return
+	}
+	switch a.Value.Kind() {
+	case stdslog.KindGroup:
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range attrs {
+			addAttr(fs, ts, prefix, ga)
+		}
+		return
+	}
+	if prefix == "" && a.Key == TagsKey {
+		switch v := a.Value.Any().(type) {
+		case string:
+			ts.Add(v)
+			return
+		case []string:
+			ts.Add(v...)
+			return
+		}
+	}
+	fs.Add(Field{Key: prefix + a.Key, Value: attrValue(a.Value)})
+}
+
+func attrValue(v stdslog.Value) interface{} {
+	switch v.Kind() {
+	case stdslog.KindString:
+		return v.String()
+	case stdslog.KindInt64:
+		return v.Int64()
+	case stdslog.KindUint64:
+		return v.Uint64()
+	case stdslog.KindFloat64:
+		return v.Float64()
+	case stdslog.KindBool:
+		return v.Bool()
+	case stdslog.KindDuration:
+		return v.Duration()
+	case stdslog.KindTime:
+		return v.Time()
+	default:
+		return v.Any()
+	}
+}
+
+func (h *Handler) clone() *Handler {
+	out := &Handler{
+		logger: h.logger,
+		fields: make([]Field, len(h.fields)),
+		tags:   make([]string, len(h.tags)),
+		prefix: h.prefix,
+	}
+	copy(out.fields, h.fields)
+	copy(out.tags, h.tags)
+	return out
+}
+
+// WithAttrs returns a Handler that adds attrs to all records.
+func (h *Handler) WithAttrs(attrs []stdslog.Attr) stdslog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	out := h.clone()
+	fs := fields(out.fields)
+	ts := tags(out.tags)
+	for _, a := range attrs {
+		addAttr(&fs, &ts, out.prefix, a)
+	}
+	out.fields = fs
+	out.tags = ts
+	return out
+}
+
+// WithGroup returns a Handler that puts the attributes inside the group name.
+func (h *Handler) WithGroup(name string) stdslog.Handler {
+	if name == "" {
+		return h
+	}
+	out := h.clone()
+	out.prefix += name + "."
+	return out
+}
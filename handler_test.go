@@ -0,0 +1,186 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stdslog "log/slog"
+	"testing"
+	"time"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+	"github.com/fcavani/slog/slogtest"
+)
+
+func TestHandler(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter: buf,
+		Level:   ProtoPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	std := stdslog.New(NewHandler(logger))
+
+	std.Info(msg)
+	AssertLine(t, buf, "teste - info - benchmark log test")
+
+	std.Warn(msg, "user", "john", "id", 42)
+	AssertLine(t, buf, "teste - warning - user=john id=42 - benchmark log test")
+
+	std.Debug(msg, TagsKey, []string{"tag1", "tag2"})
+	AssertLine(t, buf, "teste - debug - tag1 tag2 - benchmark log test")
+
+	std.With("service", "api").WithGroup("req").With("id", 1).Error(msg, stdslog.Group("user", "name", "john"), stdslog.Group("empty"))
+	AssertLine(t, buf, "teste - error - service=api req.id=1 req.user.name=john - benchmark log test")
+
+	std.With(TagsKey, "tag1").Info(msg)
+	AssertLine(t, buf, "teste - info - tag1 - benchmark log test")
+
+	std.Log(context.Background(), stdslog.LevelDebug-4, msg)
+	AssertLine(t, buf, "teste - protocol - benchmark log test")
+
+	logger = logger.Di().MakeDefault()
+	stdslog.New(NewHandler(logger)).Info(msg)
+	AssertLine(t, buf, "teste - info - slog/handler_test.go:56 - benchmark log test")
+}
+
+func TestHandlerEnabled(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter: buf,
+		Level:   WarnPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	h := NewHandler(logger)
+	if h.Enabled(context.Background(), stdslog.LevelInfo) {
+		t.Fatal("info is enabled")
+	}
+	if !h.Enabled(context.Background(), stdslog.LevelWarn) {
+		t.Fatal("warn isn't enabled")
+	}
+	stdslog.New(h).Info(msg)
+	AssertEOF(t, buf)
+
+	for _, l := range []Level{ProtoPrio, DebugPrio, InfoPrio, WarnPrio, ErrorPrio} {
+		if FromStdLevel(StdLevel(l)) != l {
+			t.Fatal("level conversion failed", l)
+		}
+	}
+}
+
+func TestHandlerJSON(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter:   buf,
+		Formatter: JSON,
+		Level:     DebugPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	stdslog.New(NewHandler(logger)).Info(msg, "d", time.Second, "f", 1.5, "b", true)
+	v := make(map[string]interface{})
+	err = json.Unmarshal(buf.Bytes(), &v)
+	if err != nil {
+		t.Fatal(err, buf.String())
+	}
+	if v["d"] != "1s" || v["f"] != 1.5 || v["b"] != true {
+		t.Fatalf("wrong fields: %v", v)
+	}
+}
+
+func TestHandlerTime(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+	now := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	logger := &Slog{
+		Writter:   buf,
+		Formatter: JSON,
+		Level:     DebugPrio,
+		Clock:     slogtest.NewFakeClock(now),
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	timestamp := func() time.Time {
+		var v struct{ Timestamp time.Time }
+		err := json.Unmarshal(buf.Bytes(), &v)
+		if err != nil {
+			t.Fatal(err, buf.String())
+		}
+		buf.Reset()
+		return v.Timestamp
+	}
+
+	h := NewHandler(logger)
+	recorded := time.Date(2017, 6, 7, 8, 9, 10, 0, time.UTC)
+	err = h.Handle(context.Background(), stdslog.NewRecord(recorded, stdslog.LevelInfo, msg, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts := timestamp(); !ts.Equal(recorded) {
+		t.Fatal("wrong timestamp", ts)
+	}
+
+	err = h.Handle(context.Background(), stdslog.NewRecord(time.Time{}, stdslog.LevelInfo, msg, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts := timestamp(); !ts.Equal(now) {
+		t.Fatal("wrong timestamp", ts)
+	}
+
+	logger.Print(msg)
+	if ts := timestamp(); !ts.Equal(now) {
+		t.Fatal("wrong timestamp", ts)
+	}
+}
+
+func TestHandlerTagged(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter: buf,
+		Level:   WarnPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	std := stdslog.New(NewHandler(logger.Tag("x")))
+	std.Warn("one")
+	AssertLine(t, buf, "teste - warning - x - one")
+	std.Warn("two")
+	AssertLine(t, buf, "teste - warning - x - two")
+
+	SetTagLevel("verbose", DebugPrio)
+	defer UnsetTagLevel("verbose")
+	std.Debug(msg)
+	AssertEOF(t, buf)
+	std.With(TagsKey, "verbose").Debug(msg)
+	AssertLine(t, buf, "teste - debug - x verbose - benchmark log test")
+}
@@ -5,59 +5,102 @@
 package slog
 
 import (
-	"strings"
-	"time"
+	"unicode/utf8"
 )
 
-func formatJSONTime(buf *[]byte, t time.Time) {
-	year, month, day := t.Date()
-	Itoa(buf, year, 4)
-	*buf = append(*buf, '-')
-	Itoa(buf, int(month), 2)
-	*buf = append(*buf, '-')
-	Itoa(buf, day, 2)
-	*buf = append(*buf, 'T')
-	hour, min, sec := t.Clock()
-	Itoa(buf, hour, 2)
-	*buf = append(*buf, ':')
-	Itoa(buf, min, 2)
-	*buf = append(*buf, ':')
-	Itoa(buf, sec, 2)
-	nano := t.Nanosecond()
-	*buf = append(*buf, '.')
-	Itoa(buf, nano, 9)
-}
-
 var domain = []byte("{\"Domain\":\"")
 var prio = []byte("\",\"Priority\":\"")
 var ts = []byte("\",\"Timestamp\":\"")
 var tgs = []byte("\",\"Tags\":")
 var msg = []byte(",\"Message\":\"")
 var file = []byte("\",\"File\":\"")
-var closeing = []byte("\"}\n")
+var closeing = []byte("}\n")
+
+const hex = "0123456789abcdef"
+
+// appendJSONString appends s to buf as a quoted json string. Quotes, backslashes
+// and control characters are escaped and invalid UTF-8 is replaced by U+FFFD.
+func appendJSONString(buf *[]byte, s string) {
+	*buf = append(*buf, '"')
+	appendJSONEscaped(buf, s)
+	*buf = append(*buf, '"')
+}
+
+// appendJSONEscaped is like appendJSONString but without the quotes.
+func appendJSONEscaped(buf *[]byte, s string) {
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			*buf = append(*buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				*buf = append(*buf, '\\', c)
+			case '\n':
+				*buf = append(*buf, '\\', 'n')
+			case '\r':
+				*buf = append(*buf, '\\', 'r')
+			case '\t':
+				*buf = append(*buf, '\\', 't')
+			case '\b':
+				*buf = append(*buf, '\\', 'b')
+			case '\f':
+				*buf = append(*buf, '\\', 'f')
+			default:
+				*buf = append(*buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			*buf = append(*buf, s[start:i]...)
+			*buf = append(*buf, '\\', 'u', 'f', 'f', 'f', 'd')
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 are valid json but break javascript parsers.
+		if r == '\u2028' || r == '\u2029' {
+			*buf = append(*buf, s[start:i]...)
+			*buf = append(*buf, '\\', 'u', '2', '0', '2', hex[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	*buf = append(*buf, s[start:]...)
+}
 
 // JSON convert a log entry to json.
 func JSON(l *Slog) ([]byte, error) {
-	m := strings.Replace(l.Log.FormatMessage(), "\n", " ", -1)
-	if len(m) > 0 && m[len(m)-1] == ' ' {
+	m := l.Log.msg
+	if len(m) > 0 && m[len(m)-1] == '\n' {
 		m = m[:len(m)-1]
 	}
 	buf := Pool.Get().([]byte)
 	buf = append(buf, domain...)
-	buf = append(buf, l.Log.Domain...)
+	appendJSONEscaped(&buf, string(l.Log.Domain))
 	buf = append(buf, prio...)
 	buf = append(buf, l.Log.Priority.Byte()...)
 	buf = append(buf, ts...)
-	formatJSONTime(&buf, l.Log.Timestamp)
-	buf = append(buf, l.Log.zoneBuf...)
+	l.appendTime(&buf, TimeRFC3339)
 	buf = append(buf, tgs...)
 	l.Log.Tags.EncodeJSON(&buf)
 	buf = append(buf, msg...)
-	buf = append(buf, []byte(m)...)
+	appendJSONEscaped(&buf, m)
 	buf = append(buf, file...)
 	if l.Log.DoDi {
-		buf = append(buf, []byte(l.Log.file)...)
+		appendJSONEscaped(&buf, l.Log.file)
 	}
+	buf = append(buf, '"')
+	l.Log.Fields.EncodeJSON(&buf)
 	buf = append(buf, closeing...)
 	return buf, nil
 }
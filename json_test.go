@@ -0,0 +1,105 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func TestJSONEscape(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter:   buf,
+		Formatter: JSON,
+		Level:     DebugPrio,
+	}
+	err := logger.Init("te\"ste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	messages := []struct {
+		In  string
+		Out string
+	}{
+		{"simple", "simple"},
+		{"quote \" backslash \\ tab \t", "quote \" backslash \\ tab \t"},
+		{"line1\nline2\n", "line1\nline2"},
+		{"ctrl \x00\x01\x1f\r\b\f", "ctrl \x00\x01\x1f\r\b\f"},
+		{"utf8 ção 日本  ", "utf8 ção 日本  "},
+		{"invalid \xff\xfe end", "invalid �� end"},
+	}
+
+	for _, m := range messages {
+		logger.Tag("a\"b", "c\\d").Println(m.In)
+		line := buf.Bytes()
+		if !json.Valid(line) {
+			t.Fatalf("invalid json: %q", line)
+		}
+		AssertJson(t, buf, &jsonEntry{
+			Domain:   "te\"ste",
+			Priority: "info",
+			Tags:     []string{"a\"b", "c\\d"},
+			Message:  m.Out,
+		})
+	}
+
+	logger.With("k\"ey", "va\nl\xff").Println(msg)
+	v := make(map[string]interface{})
+	err = json.Unmarshal(buf.Bytes(), &v)
+	if err != nil {
+		t.Fatal(err, buf.String())
+	}
+	buf.Reset()
+	if v["k\"ey"] != "va\nl�" {
+		t.Fatalf("wrong field: %v", v)
+	}
+
+	logger.With("Message", "spoof", "Priority", "debug").Println(msg)
+	v = make(map[string]interface{})
+	err = json.Unmarshal(buf.Bytes(), &v)
+	if err != nil {
+		t.Fatal(err, buf.String())
+	}
+	buf.Reset()
+	if v["Message"] != msg || v["Priority"] != "info" {
+		t.Fatalf("entry replaced by fields: %v", v)
+	}
+	if v["fields.Message"] != "spoof" || v["fields.Priority"] != "debug" {
+		t.Fatalf("wrong fields: %v", v)
+	}
+}
+
+func TestJSONAllocs(t *testing.T) {
+	logger := &Slog{
+		Writter:   &writerCloser{bytes.NewBuffer([]byte{})},
+		Formatter: JSON,
+		Level:     DebugPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	logger.Log.Tags.Add("tag1", "tag\"2")
+	logger.Log.Message("a \"quoted\"\nmessage\twith escapes\n")
+
+	// Warm up the pool.
+	buf, _ := JSON(logger)
+	Pool.Put(buf[:0])
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ := JSON(logger)
+		Pool.Put(buf[:0])
+	})
+	if allocs > 1 {
+		t.Fatalf("JSON allocates %v times per run", allocs)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func TestEnabled(t *testing.T) {
+	logger := &Slog{
+		Writter: &writerCloser{bytes.NewBuffer([]byte{})},
+		Level:   WarnPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	if logger.Enabled(InfoPrio) {
+		t.Fatal("info is enabled")
+	}
+	if !logger.Enabled(WarnPrio) || !logger.Enabled(ErrorPrio) {
+		t.Fatal("warn or error isn't enabled")
+	}
+	if !logger.SetLevel(InfoPrio).Enabled(InfoPrio) {
+		t.Fatal("info isn't enabled")
+	}
+}
+
+func TestLazy(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter: buf,
+		Level:   InfoPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	called := false
+	logger.Lazy(DebugPrio, func() string {
+		called = true
+		return msg
+	})
+	AssertEOF(t, buf)
+	if called {
+		t.Fatal("fn called for a discarded entry")
+	}
+
+	logger.Lazy(InfoPrio, func() string {
+		called = true
+		return msg
+	})
+	AssertLine(t, buf, "teste - info - benchmark log test")
+	if !called {
+		t.Fatal("fn not called")
+	}
+
+	logger.Tag("tag1").Lazyf(ErrorPrio, "%v %v", "benchmark", "log test")
+	AssertLine(t, buf, "teste - error - tag1 - benchmark log test")
+
+	logger.Tag("tag1").Lazyf(DebugPrio, "%v", msg)
+	AssertEOF(t, buf)
+
+	logger.Tag("tag1").DebugLevel().Print(msg)
+	AssertEOF(t, buf)
+
+	logger.Di().Lazyf(WarnPrio, "%v", msg)
+	AssertLine(t, buf, "teste - warning - slog/lazy_test.go:77 - benchmark log test")
+}
+
+func TestFreeLazy(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	err := SetOutput("teste", InfoPrio, buf, nil, nil, 100)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	if Enabled(DebugPrio) {
+		t.Fatal("debug is enabled")
+	}
+
+	Lazy(DebugPrio, func() string {
+		t.Fatal("fn called for a discarded entry")
+		return ""
+	})
+	AssertEOF(t, buf)
+
+	Lazyf(WarnPrio, "%v", msg)
+	AssertLine(t, buf, "teste - warning - benchmark log test")
+
+	Lazy(ErrorPrio, func() string { return msg })
+	AssertLine(t, buf, "teste - error - benchmark log test")
+}
+
+// constMsg is a constant, so passing it as interface{} doesn't allocate.
+const constMsg = "benchmark log test"
+
+func newDisabledLogger(tb testing.TB) *Slog {
+	file, err := os.OpenFile(os.DevNull, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		tb.Fatal(e.Trace(e.Forward(err)))
+	}
+	logger := &Slog{
+		Writter: file,
+		Level:   ErrorPrio,
+	}
+	err = logger.Init("teste", numlogs)
+	if err != nil {
+		tb.Fatal(e.Trace(e.Forward(err)))
+	}
+	return logger
+}
+
+func TestDisabledAllocs(t *testing.T) {
+	logger := newDisabledLogger(t)
+	n := 0
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Print(constMsg)
+		logger.Printf("%v", constMsg)
+		logger.Warn(constMsg)
+		if logger.Enabled(DebugPrio) {
+			logger.DebugLevel().Print(constMsg)
+		}
+		logger.Lazy(DebugPrio, func() string {
+			return strconv.Itoa(n)
+		})
+	})
+	if allocs != 0 {
+		t.Fatal("disabled entries allocated", allocs)
+	}
+}
+
+func BenchmarkSlogDisabledPrint(b *testing.B) {
+	logger := newDisabledLogger(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Print(constMsg)
+	}
+}
+
+func BenchmarkSlogDisabledDebugLevel(b *testing.B) {
+	logger := newDisabledLogger(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.DebugLevel().Print(constMsg)
+	}
+}
+
+func BenchmarkSlogDisabledEnabled(b *testing.B) {
+	logger := newDisabledLogger(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if logger.Enabled(DebugPrio) {
+			logger.DebugLevel().Printf("%v %v", msg, i)
+		}
+	}
+}
+
+func BenchmarkSlogDisabledLazy(b *testing.B) {
+	logger := newDisabledLogger(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Lazy(DebugPrio, func() string {
+			return msg + strconv.Itoa(i)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fcavani/e"
+)
+
+// LevelEnv is the environment variable with the level spec applied when the
+// package is initialized.
+const LevelEnv = "SLOG_LEVEL"
+
+// tagPrefix is the prefix of the keys of the spec that are tags.
+const tagPrefix = "tag:"
+
+// specLevel is the level of the last spec applied, used by SetOutput and by
+// the loggers initialized without Level.
+var specLevel int32
+
+var tagsLck sync.RWMutex
+var tagLevels = make(map[string]Level)
+var numTagLevels int32
+
+// SetTagLevel sets the level of the entries with the tag. If the entry has
+// more than one tag with a level, the lowest level is used. The tag level has
+// precedence over the domain level and the Slog Level.
+func SetTagLevel(tag string, level Level) {
+	tagsLck.Lock()
+	defer tagsLck.Unlock()
+	tagLevels[tag] = level
+	atomic.StoreInt32(&numTagLevels, int32(len(tagLevels)))
+}
+
+// UnsetTagLevel removes the level of tag set by SetTagLevel.
+func UnsetTagLevel(tag string) {
+	tagsLck.Lock()
+	defer tagsLck.Unlock()
+	delete(tagLevels, tag)
+	atomic.StoreInt32(&numTagLevels, int32(len(tagLevels)))
+}
+
+// TagLevels returns a copy of the levels set by SetTagLevel.
+func TagLevels() map[string]Level {
+	tagsLck.RLock()
+	defer tagsLck.RUnlock()
+	out := make(map[string]Level, len(tagLevels))
+	for tag, level := range tagLevels {
+		out[tag] = level
+	}
+	return out
+}
+
+func tagLevel(ts tags) (Level, bool) {
+	if len(ts) == 0 || atomic.LoadInt32(&numTagLevels) == 0 {
+		return 0, false
+	}
+	tagsLck.RLock()
+	defer tagsLck.RUnlock()
+	var min Level
+	found := false
+	for _, tag := range ts {
+		level, ok := tagLevels[tag]
+		if ok && (!found || level < min) {
+			min = level
+			found = true
+		}
+	}
+	return min, found
+}
+
+// LevelSpec is the verbosity of the loggers: the level of the default logger
+// and levels for domain prefixes and tags.
+type LevelSpec struct {
+	// Level is the level of the default logger, zero keeps its level. It's
+	// also the level of the default loggers set later by SetOutput and of
+	// the loggers initialized without Level.
+	Level Level
+	// Domains are the levels of the domain prefixes.
+	Domains map[string]Level
+	// Tags are the levels of the tags.
+	Tags map[string]Level
+}
+
+// ParseLevelSpec parses a list of levels separated by commas. An entry without
+// key is the default level, an entry key=level sets the level of a domain
+// prefix and tag:name=level sets the level of a tag:
+//
+//	info,api.db=debug,tag:cache=proto
+//
+// The levels are parsed by ParseLevel.
+func ParseLevelSpec(spec string) (*LevelSpec, error) {
+	s := &LevelSpec{
+		Domains: make(map[string]Level),
+		Tags:    make(map[string]Level),
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.IndexByte(entry, '=')
+		if i < 0 {
+			if s.Level != 0 {
+				return nil, e.New("default level set twice in %q", entry)
+			}
+			level, err := ParseLevel(entry)
+			if err != nil {
+				return nil, e.Push(err, e.New("invalid default level %q", entry))
+			}
+			s.Level = level
+			continue
+		}
+		key := strings.TrimSpace(entry[:i])
+		level, err := ParseLevel(strings.TrimSpace(entry[i+1:]))
+		if err != nil {
+			return nil, e.Push(err, e.New("invalid level in %q", entry))
+		}
+		if strings.HasPrefix(key, tagPrefix) {
+			tag := key[len(tagPrefix):]
+			if tag == "" {
+				return nil, e.New("empty tag in %q", entry)
+			}
+			s.Tags[tag] = level
+			continue
+		}
+		if key == "" {
+			return nil, e.New("empty domain in %q", entry)
+		}
+		s.Domains[key] = level
+	}
+	return s, nil
+}
+
+// String returns the spec in the form parsed by ParseLevelSpec.
+func (s *LevelSpec) String() string {
+	var entries []string
+	if s.Level != 0 {
+		entries = append(entries, s.Level.String())
+	}
+	var keyed []string
+	for domain, level := range s.Domains {
+		keyed = append(keyed, domain+"="+level.String())
+	}
+	for tag, level := range s.Tags {
+		keyed = append(keyed, tagPrefix+tag+"="+level.String())
+	}
+	sort.Strings(keyed)
+	return strings.Join(append(entries, keyed...), ",")
+}
+
+// Apply replaces the domain and tag levels by the levels of the spec and
+// changes the level of the default logger with ChangeLevel, so the level can
+// be changed again with ChangeLevel or SetLevel. The level is kept for the
+// loggers created after, until a spec without level is applied.
+func (s *LevelSpec) Apply() {
+	atomic.StoreInt32(&specLevel, int32(s.Level))
+	if s.Level != 0 {
+		DefaultLogger().ChangeLevel(s.Level)
+	}
+
+	domainsLck.Lock()
+	domains = make(map[string]Level, len(s.Domains))
+	for domain, level := range s.Domains {
+		domains[domain] = level
+	}
+	atomic.StoreInt32(&numDomains, int32(len(domains)))
+	domainsLck.Unlock()
+
+	tagsLck.Lock()
+	tagLevels = make(map[string]Level, len(s.Tags))
+	for tag, level := range s.Tags {
+		tagLevels[tag] = level
+	}
+	atomic.StoreInt32(&numTagLevels, int32(len(tagLevels)))
+	tagsLck.Unlock()
+}
+
+// defaultLevel returns the level of the last spec applied, zero if it has
+// none.
+func defaultLevel() Level {
+	return Level(atomic.LoadInt32(&specLevel))
+}
+
+// CurrentLevelSpec returns the level of the default logger and the domain and
+// tag levels in use.
+func CurrentLevelSpec() *LevelSpec {
+	return &LevelSpec{
+		Level:   DefaultLogger().baseLevel(),
+		Domains: DomainLevels(),
+		Tags:    TagLevels(),
+	}
+}
+
+// LoadLevelSpec parses and applies the spec.
+func LoadLevelSpec(spec string) error {
+	s, err := ParseLevelSpec(spec)
+	if err != nil {
+		return e.Forward(err)
+	}
+	s.Apply()
+	return nil
+}
+
+// loadLevelEnv applies the spec in LevelEnv, it's called after the default
+// logger is created.
+func loadLevelEnv() {
+	spec := os.Getenv(LevelEnv)
+	if spec == "" {
+		return
+	}
+	err := LoadLevelSpec(spec)
+	if err != nil {
+		println("SLOG: invalid "+LevelEnv+":", err.Error())
+	}
+}
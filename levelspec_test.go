@@ -0,0 +1,154 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func TestParseLevelSpec(t *testing.T) {
+	s, err := ParseLevelSpec(" info, api.db=debug ,tag:cache=proto,api=warn")
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	if s.Level != InfoPrio {
+		t.Fatal("wrong default level", s.Level)
+	}
+	if len(s.Domains) != 2 || s.Domains["api.db"] != DebugPrio || s.Domains["api"] != WarnPrio {
+		t.Fatal("wrong domain levels", s.Domains)
+	}
+	if len(s.Tags) != 1 || s.Tags["cache"] != ProtoPrio {
+		t.Fatal("wrong tag levels", s.Tags)
+	}
+	if str := s.String(); str != "info,api.db=debug,api=warning,tag:cache=protocol" {
+		t.Fatal("wrong string", str)
+	}
+
+	s, err = ParseLevelSpec("")
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	if s.Level != 0 || len(s.Domains) != 0 || len(s.Tags) != 0 {
+		t.Fatal("empty spec isn't empty", s)
+	}
+
+	for _, spec := range []string{"bogus", "info,debug", "api=bogus", "=info", "tag:=info"} {
+		_, err = ParseLevelSpec(spec)
+		if err == nil {
+			t.Fatal("spec didn't fail", spec)
+		}
+	}
+}
+
+func TestLevelSpecApply(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	err := SetOutput("api", ErrorPrio, buf, nil, nil, 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	logger := DefaultLogger()
+	defer (&LevelSpec{}).Apply()
+
+	err = LoadLevelSpec("info,api.db=debug,tag:cache=proto")
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	logger.Print(msg)
+	AssertLine(t, buf, "api - info - benchmark log test")
+
+	logger.DebugLevel().Print(msg)
+	AssertEOF(t, buf)
+
+	logger.Sub("db").DebugLevel().Print(msg)
+	AssertLine(t, buf, "api.db - debug - benchmark log test")
+
+	logger.Tag("cache").ProtoLevel().Print(msg)
+	AssertLine(t, buf, "api - protocol - cache - benchmark log test")
+
+	if str := CurrentLevelSpec().String(); str != "info,api.db=debug,tag:cache=protocol" {
+		t.Fatal("wrong current spec", str)
+	}
+
+	err = LoadLevelSpec("api=bogus")
+	if err == nil {
+		t.Fatal("invalid spec loaded")
+	}
+
+	// The level of the spec is the level of the default logger, the other
+	// loggers keep their levels.
+	other := &Slog{
+		Writter: buf,
+		Level:   ErrorPrio,
+	}
+	err = other.Init("other", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	other.Print(msg)
+	AssertEOF(t, buf)
+
+	err = LoadLevelSpec("error")
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	logger.Print(msg)
+	AssertEOF(t, buf)
+
+	SetLevel(DebugPrio)
+	if !logger.Enabled(DebugPrio) {
+		t.Fatal("SetLevel didn't change the level")
+	}
+	logger.DebugLevel().Print(msg)
+	AssertLine(t, buf, "api - debug - benchmark log test")
+	if CurrentLevelSpec().Level != DebugPrio {
+		t.Fatal("wrong current level", CurrentLevelSpec().Level)
+	}
+
+	(&LevelSpec{}).Apply()
+
+	logger.Sub("db").ProtoLevel().Print(msg)
+	AssertEOF(t, buf)
+}
+
+func TestLevelSpecSetOutput(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+	defer (&LevelSpec{}).Apply()
+
+	err := LoadLevelSpec("debug,api.db=proto")
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	err = SetOutput("api", InfoPrio, buf, nil, nil, 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	DebugLevel().Print(msg)
+	AssertLine(t, buf, "api - debug - benchmark log test")
+	Sub("db").ProtoLevel().Print(msg)
+	AssertLine(t, buf, "api.db - protocol - benchmark log test")
+
+	// Loggers without Level also use the level of the spec.
+	other := &Slog{Writter: buf}
+	err = other.Init("other", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	other.DebugLevel().Print(msg)
+	AssertLine(t, buf, "other - debug - benchmark log test")
+
+	(&LevelSpec{}).Apply()
+	err = SetOutput("api", InfoPrio, buf, nil, nil, 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	DebugLevel().Print(msg)
+	AssertEOF(t, buf)
+}
@@ -0,0 +1,137 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+var lfTime = []byte("time=")
+var lfLevel = []byte(" level=")
+var lfDomain = []byte(" domain=")
+var lfTags = []byte(" tags=")
+var lfFile = []byte(" file=")
+var lfMsg = []byte(" msg=")
+
+// logfmtNeedsQuote returns true if s can't be a bare logfmt value.
+func logfmtNeedsQuote(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c == '=' || c == '"' || c == '\\' || c == 0x7f || c >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// appendLogfmtTime appends the timestamp of the entry, quoted if the layout
+// has spaces or other characters that need quotes.
+func appendLogfmtTime(buf *[]byte, l *Slog) {
+	start := len(*buf)
+	l.appendTime(buf, TimeRFC3339)
+	for i := start; i < len(*buf); i++ {
+		c := (*buf)[i]
+		if c <= ' ' || c == '=' || c == '"' || c == '\\' || c == 0x7f || c >= utf8.RuneSelf {
+			s := string((*buf)[start:])
+			*buf = (*buf)[:start]
+			appendJSONString(buf, s)
+			return
+		}
+	}
+}
+
+func appendLogfmtString(buf *[]byte, s string) {
+	if logfmtNeedsQuote(s) {
+		appendJSONString(buf, s)
+		return
+	}
+	*buf = append(*buf, s...)
+}
+
+// appendLogfmtKey appends the key replacing the characters not allowed in a
+// logfmt key by underscores.
+func appendLogfmtKey(buf *[]byte, key string) {
+	if len(key) == 0 {
+		*buf = append(*buf, '_')
+		return
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c <= ' ' || c == '=' || c == '"' || c >= 0x7f {
+			c = '_'
+		}
+		*buf = append(*buf, c)
+	}
+}
+
+func appendLogfmtValue(buf *[]byte, v interface{}) {
+	switch val := v.(type) {
+	case string:
+		appendLogfmtString(buf, val)
+	case []byte:
+		appendLogfmtString(buf, string(val))
+	case error:
+		appendLogfmtString(buf, val.Error())
+	case fmt.Stringer:
+		appendLogfmtString(buf, val.String())
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		appendTextValue(buf, val)
+	default:
+		appendLogfmtString(buf, fmt.Sprint(val))
+	}
+}
+
+// Logfmt formats the log entry in the logfmt format:
+//
+//	time=2006-01-02T15:04:05.000000000-03:00 level=info domain=app tags="a b" file=app/main.go:10 msg="the message" key=value
+//
+// tags and file are only present if the entry has tags or debug information.
+// The fields of the entry are appended after the message.
+func Logfmt(l *Slog) ([]byte, error) {
+	m := l.Log.msg
+	if len(m) > 0 && m[len(m)-1] == '\n' {
+		m = m[:len(m)-1]
+	}
+	buf := Pool.Get().([]byte)
+	buf = append(buf, lfTime...)
+	appendLogfmtTime(&buf, l)
+	buf = append(buf, lfLevel...)
+	appendLogfmtString(&buf, l.Log.Priority.String())
+	buf = append(buf, lfDomain...)
+	appendLogfmtString(&buf, string(l.Log.Domain))
+	if t := *l.Log.Tags; len(t) > 0 {
+		buf = append(buf, lfTags...)
+		if len(t) == 1 {
+			appendLogfmtString(&buf, t[0])
+		} else {
+			buf = append(buf, '"')
+			for i := 0; i < len(t); i++ {
+				if i > 0 {
+					buf = append(buf, ' ')
+				}
+				appendJSONEscaped(&buf, t[i])
+			}
+			buf = append(buf, '"')
+		}
+	}
+	if l.Log.DoDi {
+		buf = append(buf, lfFile...)
+		appendLogfmtString(&buf, l.Log.file)
+	}
+	buf = append(buf, lfMsg...)
+	appendLogfmtString(&buf, m)
+	for _, f := range *l.Log.Fields {
+		buf = append(buf, ' ')
+		appendLogfmtKey(&buf, f.Key)
+		buf = append(buf, '=')
+		appendLogfmtValue(&buf, f.Value)
+	}
+	buf = append(buf, '\n')
+	return buf, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func AssertLogfmt(t *testing.T, buf *writerCloser, line string) {
+	l, err := buf.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if !strings.HasPrefix(l, "time=") {
+		t.Fatalf("log entry without time: %q", l)
+	}
+	i := strings.IndexByte(l, ' ')
+	if i < 0 {
+		t.Fatalf("invalid log entry: %q", l)
+	}
+	l = l[i+1 : len(l)-1]
+	if l != line {
+		t.Fatalf("log entry is wrong: %q != %q", l, line)
+	}
+}
+
+func TestLogfmt(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter:   buf,
+		Formatter: Logfmt,
+		Level:     DebugPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	logger.Println(msg)
+	AssertLogfmt(t, buf, `level=info domain=teste msg="benchmark log test"`)
+
+	logger.Tag("tag1").ErrorLevel().Print("single")
+	AssertLogfmt(t, buf, `level=error domain=teste tags=tag1 msg=single`)
+
+	logger.Tag("tag1", "tag2").Di().Print(msg)
+	AssertLogfmt(t, buf, `level=info domain=teste tags="tag1 tag2" file=slog/logfmt_test.go:55 msg="benchmark log test"`)
+
+	logger.Print("quote \" back\\slash\nnew line\t=")
+	AssertLogfmt(t, buf, `level=info domain=teste msg="quote \" back\\slash\nnew line\t="`)
+
+	logger.Print("")
+	AssertLogfmt(t, buf, `level=info domain=teste msg=""`)
+
+	logger.With("user", "john doe", "id", 42, "ok", true, "bad key", "x=y", "err", errors.New("failed")).Print("ação")
+	AssertLogfmt(t, buf, `level=info domain=teste msg="ação" user="john doe" id=42 ok=true bad_key="x=y" err=failed`)
+}
@@ -0,0 +1,176 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"io"
+	"sync"
+
+	"github.com/fcavani/e"
+)
+
+// Sink is one destination of a Multi committer.
+type Sink struct {
+	// Writter receives the formatted entries.
+	Writter io.WriteCloser
+	// Formatter formats the entry for this sink, if nil the Slog Formatter is
+	// used and its output is shared with the other sinks without Formatter.
+	Formatter func(l *Slog) ([]byte, error)
+	// FormatKey identifies the Formatter. The sinks with the same FormatKey
+	// share the entry formatted by the Formatter of the first one. If empty
+	// the entry is formatted only for this sink.
+	FormatKey string
+	// Level is the minimum priority of the entries sent to this sink. Zero
+	// accepts all entries.
+	Level Level
+	// Filter filters the entries for this sink. If return true the entry is
+	// sent to the sink.
+	Filter func(l *Slog) bool
+
+	lck sync.Mutex
+}
+
+// Multi is a committer that sends each entry to several sinks, each one with
+// its own writer, formatter, level and filter. The entry is formatted once for
+// all sinks without Formatter, with the Slog Formatter, once for each
+// FormatKey and once for each other sink. The Slog Level must be less or
+// equal to the sink levels, otherwise the entries are discarded before
+// reaching the sinks. Multi is also a io.WriteCloser that can be used as the
+// Slog Writter, so Flush and Close reach all sinks:
+//
+//	multi := &slog.Multi{Sinks: []*slog.Sink{
+//		{Writter: os.Stdout, Level: slog.InfoPrio},
+//		{Writter: file, Formatter: slog.JSON, FormatKey: "json", Level: slog.DebugPrio},
+//		{Writter: conn, Formatter: slog.JSON, FormatKey: "json", Level: slog.ErrorPrio},
+//	}}
+//	logger := &slog.Slog{Level: slog.DebugPrio, Commit: multi.Commit, Writter: multi}
+type Multi struct {
+	Sinks []*Sink
+}
+
+type formatted struct {
+	key string
+	buf []byte
+	err error
+}
+
+// Commit formats and sends the entry to the sinks.
+func (m *Multi) Commit(sl *Slog) {
+	sl.stamp()
+	if sl.Log.DoDi && sl.Log.file == "" {
+		sl.Log.file = debugInfo(sl.Log.DiLevel)
+	}
+
+	// bufs are the entries formatted for more than one sink, the key of the
+	// Slog Formatter is empty.
+	var cache [4]formatted
+	bufs := cache[:0]
+	defer func() {
+		for _, f := range bufs {
+			if f.err == nil {
+				Pool.Put(f.buf[:0])
+			}
+		}
+	}()
+
+	for _, sink := range m.Sinks {
+		if sink.Level != 0 && sl.Log.Priority < sink.Level {
+			continue
+		}
+		if sink.Filter != nil && !sink.Filter(sl) {
+			continue
+		}
+		if sink.Formatter != nil && sink.FormatKey == "" {
+			buf, err := sink.Formatter(sl)
+			if err != nil {
+				//TODO: Give to the user a nice error message.
+				println("SLOG writer failed:", err)
+				continue
+			}
+			sink.write(buf)
+			Pool.Put(buf[:0])
+			continue
+		}
+		formatter, key := sl.Formatter, ""
+		if sink.Formatter != nil {
+			formatter, key = sink.Formatter, sink.FormatKey
+		}
+		var f *formatted
+		for i := range bufs {
+			if bufs[i].key == key {
+				f = &bufs[i]
+				break
+			}
+		}
+		if f == nil {
+			buf, err := formatter(sl)
+			bufs = append(bufs, formatted{key: key, buf: buf, err: err})
+			f = &bufs[len(bufs)-1]
+		}
+		if f.err != nil {
+			//TODO: Give to the user a nice error message.
+			println("SLOG writer failed:", f.err)
+			continue
+		}
+		sink.write(f.buf)
+	}
+}
+
+func (s *Sink) write(buf []byte) {
+	s.lck.Lock()
+	_, err := s.Writter.Write(buf)
+	s.lck.Unlock()
+	if err != nil {
+		println("SLOG writer failed:", err)
+	}
+}
+
+// Write writes p to all sinks.
+func (m *Multi) Write(p []byte) (n int, err error) {
+	for _, sink := range m.Sinks {
+		sink.lck.Lock()
+		_, er := sink.Writter.Write(p)
+		sink.lck.Unlock()
+		if er != nil && err == nil {
+			err = e.Forward(er)
+		}
+	}
+	return len(p), err
+}
+
+// Flush flushes the writers of the sinks that are Flusher or Syncer.
+func (m *Multi) Flush() error {
+	var err error
+	for _, sink := range m.Sinks {
+		var er error
+		switch w := sink.Writter.(type) {
+		case Flusher:
+			er = w.Flush()
+		case Syncer:
+			er = w.Sync()
+			if er != nil && ignoreSyncErr(er) {
+				er = nil
+			}
+		}
+		if er != nil && err == nil {
+			err = e.Forward(er)
+		}
+	}
+	return err
+}
+
+// Close closes the writers of all sinks.
+func (m *Multi) Close() error {
+	var err error
+	for _, sink := range m.Sinks {
+		sink.lck.Lock()
+		er := sink.Writter.Close()
+		sink.lck.Unlock()
+		if er != nil && err == nil {
+			err = e.Forward(er)
+		}
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func TestMulti(t *testing.T) {
+	text := &writerCloser{bytes.NewBuffer([]byte{})}
+	jsonBuf := &writerCloser{bytes.NewBuffer([]byte{})}
+	errs := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	multi := &Multi{Sinks: []*Sink{
+		{Writter: text, Level: InfoPrio},
+		{Writter: jsonBuf, Formatter: JSON, Level: DebugPrio},
+		{Writter: errs, Level: ErrorPrio, Filter: func(sl *Slog) bool {
+			return !sl.Log.Tags.Have("nolog")
+		}},
+	}}
+
+	logger := &Slog{
+		Writter: multi,
+		Level:   DebugPrio,
+		Commit:  multi.Commit,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	logger.DebugLevel().Print(msg)
+	AssertEOF(t, text)
+	AssertEOF(t, errs)
+	AssertJson(t, jsonBuf, &jsonEntry{
+		Domain:   "teste",
+		Priority: "debug",
+		Tags:     []string{},
+		Message:  msg,
+	})
+
+	logger.Error(msg)
+	AssertLine(t, text, "teste - error - benchmark log test")
+	AssertLine(t, errs, "teste - error - benchmark log test")
+	AssertJson(t, jsonBuf, &jsonEntry{
+		Domain:   "teste",
+		Priority: "error",
+		Tags:     []string{},
+		Message:  msg,
+	})
+
+	logger.Tag("nolog").Error(msg)
+	AssertLine(t, text, "teste - error - nolog - benchmark log test")
+	AssertEOF(t, errs)
+	jsonBuf.Reset()
+
+	err = logger.Flush()
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	err = logger.Close()
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+}
+
+func TestMultiFormatOnce(t *testing.T) {
+	var calls int
+	formatter := func(sl *Slog) ([]byte, error) {
+		calls++
+		return JSON(sl)
+	}
+	a := &writerCloser{bytes.NewBuffer([]byte{})}
+	b := &writerCloser{bytes.NewBuffer([]byte{})}
+	c := &writerCloser{bytes.NewBuffer([]byte{})}
+	d := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	multi := &Multi{Sinks: []*Sink{
+		{Writter: a, Formatter: formatter, FormatKey: "json"},
+		{Writter: b, Formatter: formatter, FormatKey: "json"},
+		{Writter: c},
+		{Writter: d},
+	}}
+	logger := &Slog{
+		Level:  DebugPrio,
+		Commit: multi.Commit,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	logger.Print(msg)
+	if calls != 1 {
+		t.Fatal("formatter called", calls, "times")
+	}
+	if a.String() != b.String() {
+		t.Fatal("different outputs", a.String(), b.String())
+	}
+	AssertLine(t, c, "teste - info - benchmark log test")
+	AssertLine(t, d, "teste - info - benchmark log test")
+
+	// Without FormatKey each sink formats the entry.
+	multi.Sinks[1].FormatKey = ""
+	logger.Print(msg)
+	if calls != 3 {
+		t.Fatal("formatter called", calls, "times")
+	}
+}
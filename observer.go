@@ -0,0 +1,238 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// TB is the part of testing.TB used by the test helpers, *testing.T and
+// *testing.B implement it.
+type TB interface {
+	Helper()
+	Logf(format string, args ...interface{})
+	Fatal(args ...interface{})
+	Failed() bool
+}
+
+// cleaner is implemented by the testing.TB of go 1.14 and newer.
+type cleaner interface {
+	Cleanup(func())
+}
+
+// ObservedEntry is an entry recorded by Observer.
+type ObservedEntry struct {
+	Time    time.Time
+	Level   Level
+	Domain  string
+	Tags    []string
+	Fields  []Field
+	Message string
+	// Caller is the file and the line, empty if the debug information is
+	// disabled.
+	Caller string
+}
+
+// HasTag returns true if the entry has the tag.
+func (o ObservedEntry) HasTag(tag string) bool {
+	for _, t := range o.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Field returns the value of the last field with the key.
+func (o ObservedEntry) Field(key string) (interface{}, bool) {
+	for i := len(o.Fields) - 1; i >= 0; i-- {
+		if o.Fields[i].Key == key {
+			return o.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// String returns the entry in the format of the default formatter.
+func (o ObservedEntry) String() string {
+	buf := make([]byte, 0, 128)
+	buf = append(buf, o.Domain...)
+	buf = append(buf, sep...)
+	FormatTime(&buf, o.Time)
+	buf = append(buf, sep...)
+	buf = append(buf, o.Level.Byte()...)
+	buf = append(buf, sep...)
+	if len(o.Tags) > 0 {
+		buf = append(buf, tags(o.Tags).String()...)
+		buf = append(buf, sepTags...)
+	}
+	if len(o.Fields) > 0 {
+		fields(o.Fields).encodeText(&buf)
+		buf = append(buf, sepTags...)
+	}
+	if o.Caller != "" {
+		buf = append(buf, o.Caller...)
+		buf = append(buf, sep...)
+	}
+	buf = append(buf, o.Message...)
+	return string(buf)
+}
+
+// ObservedEntries is a list of entries with query helpers.
+type ObservedEntries []ObservedEntry
+
+// Filter returns the entries for which fn returns true.
+func (o ObservedEntries) Filter(fn func(ObservedEntry) bool) ObservedEntries {
+	var out ObservedEntries
+	for _, ent := range o {
+		if fn(ent) {
+			out = append(out, ent)
+		}
+	}
+	return out
+}
+
+// Level returns the entries with the level.
+func (o ObservedEntries) Level(level Level) ObservedEntries {
+	return o.Filter(func(ent ObservedEntry) bool {
+		return ent.Level == level
+	})
+}
+
+// MinLevel returns the entries with level greater or equal to level.
+func (o ObservedEntries) MinLevel(level Level) ObservedEntries {
+	return o.Filter(func(ent ObservedEntry) bool {
+		return ent.Level >= level
+	})
+}
+
+// Domain returns the entries of the domain.
+func (o ObservedEntries) Domain(domain string) ObservedEntries {
+	return o.Filter(func(ent ObservedEntry) bool {
+		return ent.Domain == domain
+	})
+}
+
+// Tag returns the entries with the tag.
+func (o ObservedEntries) Tag(tag string) ObservedEntries {
+	return o.Filter(func(ent ObservedEntry) bool {
+		return ent.HasTag(tag)
+	})
+}
+
+// Contains returns the entries which message contains substr.
+func (o ObservedEntries) Contains(substr string) ObservedEntries {
+	return o.Filter(func(ent ObservedEntry) bool {
+		return strings.Contains(ent.Message, substr)
+	})
+}
+
+// Len returns the number of entries.
+func (o ObservedEntries) Len() int {
+	return len(o)
+}
+
+// Messages returns the messages of the entries.
+func (o ObservedEntries) Messages() []string {
+	out := make([]string, len(o))
+	for i, ent := range o {
+		out[i] = ent.Message
+	}
+	return out
+}
+
+// Observer is a committer that records the entries in memory, to be inspected
+// by tests.
+//
+//	obs := &slog.Observer{}
+//	logger := &slog.Slog{Commit: obs.Commit, Level: slog.ProtoPrio}
+type Observer struct {
+	mu      sync.Mutex
+	entries ObservedEntries
+}
+
+// NewObserver returns a logger that records all entries in the returned
+// Observer. If tb supports Cleanup, the entries are written with tb.Logf
+// when the test ends, only if it failed.
+func NewObserver(tb TB, domain string) (*Slog, *Observer) {
+	tb.Helper()
+	o := &Observer{}
+	l := &Slog{
+		Level:  ProtoPrio,
+		Commit: o.Commit,
+	}
+	err := l.Init(domain, 1)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if c, ok := tb.(cleaner); ok {
+		c.Cleanup(func() {
+			tb.Helper()
+			if tb.Failed() {
+				o.Dump(tb)
+			}
+		})
+	}
+	return l, o
+}
+
+// Commit records the entry.
+func (o *Observer) Commit(sl *Slog) {
+	sl.stamp()
+	if sl.Log.DoDi && sl.Log.file == "" {
+		sl.Log.file = debugInfo(sl.Log.DiLevel)
+	}
+	ent := ObservedEntry{
+		Time:    sl.Log.Timestamp,
+		Level:   sl.Log.Priority,
+		Domain:  string(sl.Log.Domain),
+		Message: strings.TrimSuffix(sl.Log.msg, "\n"),
+	}
+	if len(*sl.Log.Tags) > 0 {
+		ent.Tags = append([]string(nil), *sl.Log.Tags...)
+	}
+	if len(*sl.Log.Fields) > 0 {
+		ent.Fields = append([]Field(nil), *sl.Log.Fields...)
+	}
+	if sl.Log.DoDi {
+		ent.Caller = sl.Log.file
+	}
+	o.mu.Lock()
+	o.entries = append(o.entries, ent)
+	o.mu.Unlock()
+}
+
+// All returns a copy of the recorded entries.
+func (o *Observer) All() ObservedEntries {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append(ObservedEntries(nil), o.entries...)
+}
+
+// TakeAll returns the recorded entries and clears the Observer.
+func (o *Observer) TakeAll() ObservedEntries {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := o.entries
+	o.entries = nil
+	return out
+}
+
+// Len returns the number of recorded entries.
+func (o *Observer) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// Dump writes the recorded entries with tb.Logf.
+func (o *Observer) Dump(tb TB) {
+	tb.Helper()
+	for _, ent := range o.All() {
+		tb.Logf("%v", ent)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/fcavani/slog"
+)
+
+// fakeTB records the calls of the test helpers.
+type fakeTB struct {
+	failed   bool
+	logs     []string
+	cleanups []func()
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Logf(format string, args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Fatal(args ...interface{}) {
+	f.failed = true
+	f.logs = append(f.logs, fmt.Sprint(args...))
+}
+
+func (f *fakeTB) Failed() bool {
+	return f.failed
+}
+
+func (f *fakeTB) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *fakeTB) end() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestObserver(t *testing.T) {
+	logger, obs := NewObserver(t, "teste")
+
+	logger.Print(msg)
+	logger.Tag("tag1", "tag2").With("id", 42).Error("error message")
+	logger.Sub("db").DebugLevel().Printf("query %v", 1)
+	logger.Di().Warn(msg)
+	logger.ProtoLevel().Tag("tag2").Print("proto")
+
+	all := obs.All()
+	if all.Len() != 5 || obs.Len() != 5 {
+		t.Fatal("wrong number of entries", all.Len())
+	}
+	if all[0].Message != msg || all[0].Level != InfoPrio || all[0].Domain != "teste" {
+		t.Fatal("wrong entry", all[0])
+	}
+	if e := all.Level(ErrorPrio); e.Len() != 1 || e[0].Message != "error message" {
+		t.Fatal("wrong error entries", e)
+	}
+	if v, ok := all[1].Field("id"); !ok || v != 42 {
+		t.Fatal("wrong field", v)
+	}
+	if e := all.Tag("tag2"); e.Len() != 2 {
+		t.Fatal("wrong tag2 entries", e)
+	}
+	if e := all.Domain("teste.db"); e.Len() != 1 || e[0].Message != "query 1" {
+		t.Fatal("wrong db entries", e)
+	}
+	if e := all.Contains("benchmark"); e.Len() != 2 {
+		t.Fatal("wrong entries with benchmark", e)
+	}
+	if e := all.MinLevel(WarnPrio); e.Len() != 2 {
+		t.Fatal("wrong entries above warning", e)
+	}
+	if all[3].Caller != "slog/observer_test.go:53" {
+		t.Fatal("wrong caller", all[3].Caller)
+	}
+	if m := all.Tag("tag2").Messages(); strings.Join(m, ",") != "error message,proto" {
+		t.Fatal("wrong messages", m)
+	}
+	if s := all[1].String(); !strings.HasSuffix(s, " - error - tag1 tag2 - id=42 - error message") {
+		t.Fatal("wrong string", s)
+	}
+
+	if e := obs.TakeAll(); e.Len() != 5 {
+		t.Fatal("wrong number of entries", e.Len())
+	}
+	if obs.Len() != 0 {
+		t.Fatal("entries not cleared")
+	}
+}
+
+func TestObserverDump(t *testing.T) {
+	tb := &fakeTB{}
+	logger, _ := NewObserver(tb, "teste")
+	logger.Print(msg)
+	tb.end()
+	if len(tb.logs) != 0 {
+		t.Fatal("logs dumped for a test that didn't fail", tb.logs)
+	}
+
+	tb = &fakeTB{}
+	logger, _ = NewObserver(tb, "teste")
+	logger.Print(msg)
+	logger.Error("failure")
+	tb.failed = true
+	tb.end()
+	if len(tb.logs) != 2 || !strings.HasSuffix(tb.logs[1], " - error - failure") {
+		t.Fatal("wrong dump", tb.logs)
+	}
+}
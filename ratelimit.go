@@ -0,0 +1,195 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limit is the limit of a token bucket.
+type Limit struct {
+	// Rate is the number of entries per second that refill the bucket. A
+	// Limit with Rate less or equal to zero is ignored.
+	Rate float64
+	// Burst is the number of entries the bucket holds, at least one.
+	Burst int
+}
+
+// RateLimiter is a filter with token bucket limits per level, per tag and per
+// domain. An entry pass if there are tokens in all buckets that apply to it:
+// the bucket of its priority, the bucket of the longest prefix of its domain,
+// like SetDomainLevel, and the buckets of its tags. A entry suppressed by one
+// bucket may have taken tokens from the others. When a bucket that
+// suppressed entries refills, the next entry that pass by it is preceded by a
+// notice entry, tagged "ratelimit", with the number of suppressed entries.
+// The notice has the priority of the highest suppressed entry and doesn't
+// pass by the filter. The buckets are updated with atomic operations, so the
+// goroutines don't wait each other. Don't change the limits after the first
+// entry.
+//
+//	limiter := &slog.RateLimiter{
+//		Levels: map[slog.Level]slog.Limit{slog.ErrorPrio: {Rate: 10, Burst: 100}},
+//		Tags:   map[string]slog.Limit{"db": {Rate: 1, Burst: 10}},
+//	}
+//	logger := &slog.Slog{Filter: limiter.Filter}
+type RateLimiter struct {
+	// Levels are the limits of each level.
+	Levels map[Level]Limit
+	// Tags are the limits of the entries with the tag.
+	Tags map[string]Limit
+	// Domains are the limits of the domain prefixes.
+	Domains map[string]Limit
+
+	once       sync.Once
+	levels     map[Level]*bucket
+	tags       map[string]*bucket
+	domains    map[string]*bucket
+	suppressed uint64
+}
+
+// bucket is a token bucket implemented with the generic cell rate algorithm:
+// tat is the time when the bucket will be full, each entry moves it forward
+// by interval and the entry is suppressed if the bucket would need more than
+// burst to refill.
+type bucket struct {
+	name       string
+	interval   int64
+	burst      int64
+	tat        int64
+	suppressed uint64
+	prio       uint32
+}
+
+func newBucket(name string, limit Limit) *bucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	burst := limit.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	interval := int64(float64(time.Second) / limit.Rate)
+	if interval < 1 {
+		interval = 1
+	}
+	return &bucket{
+		name:     name,
+		interval: interval,
+		burst:    interval * int64(burst),
+	}
+}
+
+func (b *bucket) take(now int64) bool {
+	for {
+		tat := atomic.LoadInt64(&b.tat)
+		next := tat
+		if next < now {
+			next = now
+		}
+		next += b.interval
+		if next-now > b.burst {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&b.tat, tat, next) {
+			return true
+		}
+	}
+}
+
+func (b *bucket) suppress(prio Level) {
+	atomic.AddUint64(&b.suppressed, 1)
+	for {
+		old := atomic.LoadUint32(&b.prio)
+		if uint32(prio) <= old || atomic.CompareAndSwapUint32(&b.prio, old, uint32(prio)) {
+			return
+		}
+	}
+}
+
+func (r *RateLimiter) init() {
+	r.once.Do(func() {
+		r.levels = make(map[Level]*bucket, len(r.Levels))
+		for level, limit := range r.Levels {
+			if b := newBucket("level "+level.String(), limit); b != nil {
+				r.levels[level] = b
+			}
+		}
+		r.tags = make(map[string]*bucket, len(r.Tags))
+		for tag, limit := range r.Tags {
+			if b := newBucket("tag "+tag, limit); b != nil {
+				r.tags[tag] = b
+			}
+		}
+		r.domains = make(map[string]*bucket, len(r.Domains))
+		for prefix, limit := range r.Domains {
+			if b := newBucket("domain "+strconv.Quote(prefix), limit); b != nil {
+				r.domains[prefix] = b
+			}
+		}
+	})
+}
+
+func (r *RateLimiter) domain(domain []byte) *bucket {
+	if len(r.domains) == 0 {
+		return nil
+	}
+	for i := len(domain); i >= 0; i-- {
+		if i > 0 && i < len(domain) && domain[i] != DomainSep {
+			continue
+		}
+		if b, ok := r.domains[string(domain[:i])]; ok {
+			return b
+		}
+	}
+	return nil
+}
+
+// Filter returns true if the entry pass the limits.
+func (r *RateLimiter) Filter(sl *Slog) bool {
+	r.init()
+	now := sl.now().UnixNano()
+	if b := r.levels[sl.Log.Priority]; b != nil && !r.take(sl, b, now) {
+		return false
+	}
+	if b := r.domain(sl.Log.Domain); b != nil && !r.take(sl, b, now) {
+		return false
+	}
+	if len(r.tags) == 0 {
+		return true
+	}
+	for _, tag := range *sl.Log.Tags {
+		if b := r.tags[tag]; b != nil && !r.take(sl, b, now) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *RateLimiter) take(sl *Slog, b *bucket, now int64) bool {
+	if !b.take(now) {
+		b.suppress(sl.Log.Priority)
+		atomic.AddUint64(&r.suppressed, 1)
+		return false
+	}
+	if atomic.LoadUint64(&b.suppressed) == 0 {
+		return true
+	}
+	n := atomic.SwapUint64(&b.suppressed, 0)
+	prio := Level(atomic.SwapUint32(&b.prio, 0))
+	if n > 0 {
+		msg := strconv.FormatUint(n, 10) + " entries suppressed by the rate limit of " + b.name
+		notice(sl, prio, "ratelimit", n, msg)
+	}
+	return true
+}
+
+// Suppressed returns the number of entries suppressed since the limiter was
+// created.
+func (r *RateLimiter) Suppressed() uint64 {
+	return atomic.LoadUint64(&r.suppressed)
+}
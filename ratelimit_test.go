@@ -0,0 +1,118 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/fcavani/slog"
+)
+
+func TestRateLimiterLevel(t *testing.T) {
+	limiter := &RateLimiter{
+		Levels: map[Level]Limit{
+			ErrorPrio: {Rate: 1, Burst: 2},
+			InfoPrio:  {Rate: 0, Burst: 1},
+		},
+	}
+	logger, buf, clock := newFakeClockLogger(t, &Slog{Filter: limiter.Filter}, "api")
+
+	for i := 0; i < 5; i++ {
+		logger.Errorf("error %v", i)
+		logger.Print(msg)
+	}
+	l := lines(buf)
+	if len(l) != 7 {
+		t.Fatal("wrong number of entries", len(l), l)
+	}
+	if !strings.HasSuffix(l[0], "error 0") || !strings.HasSuffix(l[2], "error 1") {
+		t.Fatal("wrong entries", l)
+	}
+	if limiter.Suppressed() != 3 {
+		t.Fatal("wrong suppressed", limiter.Suppressed())
+	}
+
+	clock.Add(500 * time.Millisecond)
+	logger.Error("still empty")
+	if len(lines(buf)) != 0 {
+		t.Fatal("bucket refilled too soon")
+	}
+
+	clock.Add(500 * time.Millisecond)
+	logger.Error("refilled")
+	l = lines(buf)
+	if len(l) != 2 {
+		t.Fatal("wrong number of entries", l)
+	}
+	expected := " - error - ratelimit - suppressed=4 - 4 entries suppressed by the rate limit of level error"
+	if !strings.HasSuffix(l[0], expected) {
+		t.Fatalf("wrong notice %q", l[0])
+	}
+	if !strings.HasSuffix(l[1], "refilled") {
+		t.Fatalf("wrong entry %q", l[1])
+	}
+
+	clock.Add(10 * time.Second)
+	for i := 0; i < 3; i++ {
+		logger.Error("burst")
+	}
+	if l = lines(buf); len(l) != 2 {
+		t.Fatal("bucket holds more than the burst", l)
+	}
+}
+
+func TestRateLimiterTagDomain(t *testing.T) {
+	limiter := &RateLimiter{
+		Tags:    map[string]Limit{"db": {Rate: 1}},
+		Domains: map[string]Limit{"api.cache": {Rate: 1}},
+	}
+	logger, buf, _ := newFakeClockLogger(t, &Slog{Filter: limiter.Filter}, "api")
+
+	logger.Tag("db").Print(msg)
+	logger.Tag("db", "slow").Warn(msg)
+	logger.Tag("other").Print(msg)
+	if l := lines(buf); len(l) != 2 {
+		t.Fatal("wrong number of entries", l)
+	}
+
+	cache := logger.Sub("cache")
+	cache.Print(msg)
+	cache.Sub("redis").Print(msg)
+	logger.Sub("cachex").Print(msg)
+	if l := lines(buf); len(l) != 2 {
+		t.Fatal("wrong number of entries", l)
+	}
+	if limiter.Suppressed() != 2 {
+		t.Fatal("wrong suppressed", limiter.Suppressed())
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	limiter := &RateLimiter{
+		Levels: map[Level]Limit{InfoPrio: {Rate: 1, Burst: 10}},
+	}
+	logger, buf, _ := newFakeClockLogger(t, &Slog{Filter: limiter.Filter}, "api")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Print(msg)
+			}
+		}()
+	}
+	wg.Wait()
+	if l := lines(buf); len(l) != 10 {
+		t.Fatal("wrong number of entries", len(l))
+	}
+	if limiter.Suppressed() != 790 {
+		t.Fatal("wrong suppressed", limiter.Suppressed())
+	}
+}
@@ -0,0 +1,505 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fcavani/e"
+)
+
+// BackupNaming defines how the rotated files are named.
+type BackupNaming uint8
+
+const (
+	// NumberedBackups names the backups file.log.1, file.log.2, ... The backup
+	// with the number 1 is the newest.
+	NumberedBackups BackupNaming = iota
+	// TimestampBackups names the backups with the time of the rotation, like
+	// file-2006-01-02T15-04-05.000.log.
+	TimestampBackups
+)
+
+// DefaultBackupTimeLayout is the layout used by TimestampBackups if
+// RotatingFile.TimeLayout is empty.
+const DefaultBackupTimeLayout = "2006-01-02T15-04-05.000"
+
+const gzExt = ".gz"
+
+// RotatingFile is an io.WriteCloser that writes to a file and rotates it when
+// the file grows beyond MaxSize, when Interval elapses or both. It can be used
+// as the Writter of Slog.
+type RotatingFile struct {
+	// Filename is the path of the log file.
+	Filename string
+	// MaxSize is the maximum size in bytes of the file before it's rotated.
+	// Zero disables the rotation by size.
+	MaxSize int64
+	// Interval rotates the file every time the local wall clock crosses a
+	// multiple of Interval counted from midnight, e.g. time.Hour rotates at
+	// every hour and 24*time.Hour at the local midnight. Intervals that
+	// don't divide a day are multiples of Interval since the Unix epoch in
+	// the local zone. Zero disables the rotation by time.
+	Interval time.Duration
+	// MaxBackups is the number of rotated files that will be kept. Zero keeps
+	// all files.
+	MaxBackups int
+	// Naming is the scheme used to name the rotated files.
+	Naming BackupNaming
+	// TimeLayout is the time layout used with TimestampBackups. Backups
+	// are the files which names parse with it.
+	TimeLayout string
+	// Compress gzips the rotated files.
+	Compress bool
+	// Perm is the permission of new log files. The default is 0600.
+	Perm os.FileMode
+	// ReopenOnHUP reopens the file when the process receives SIGHUP. Use this
+	// if the file is rotated by an external tool like logrotate.
+	ReopenOnHUP bool
+
+	lck  sync.Mutex
+	file *os.File
+	size int64
+	next time.Time
+	sig  chan os.Signal
+	done chan struct{}
+}
+
+// Open opens the log file and starts the signal handling. It's called by
+// Write if the file isn't open.
+func (r *RotatingFile) Open() error {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+	return r.open()
+}
+
+func (r *RotatingFile) open() error {
+	if r.file != nil {
+		return nil
+	}
+	if r.Filename == "" {
+		return e.New("empty file name")
+	}
+	if r.Perm == 0 {
+		r.Perm = 0600
+	}
+	f, err := os.OpenFile(r.Filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, r.Perm)
+	if err != nil {
+		return e.Forward(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return e.Forward(err)
+	}
+	r.file = f
+	r.size = info.Size()
+	r.setNext(time.Now())
+	if r.ReopenOnHUP && r.sig == nil {
+		r.sig = make(chan os.Signal, 1)
+		r.done = make(chan struct{})
+		signal.Notify(r.sig, syscall.SIGHUP)
+		go r.handleSignal(r.sig, r.done)
+	}
+	return nil
+}
+
+func (r *RotatingFile) handleSignal(sig chan os.Signal, done chan struct{}) {
+	for {
+		select {
+		case <-sig:
+			r.lck.Lock()
+			// Close was called while waiting the lock.
+			if r.sig != sig {
+				r.lck.Unlock()
+				return
+			}
+			err := r.reopen()
+			r.lck.Unlock()
+			if err != nil {
+				println("SLOG reopen failed:", err.Error())
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+func (r *RotatingFile) setNext(now time.Time) {
+	if r.Interval <= 0 {
+		return
+	}
+	const day = 24 * time.Hour
+	if r.Interval > day || day%r.Interval != 0 {
+		_, offset := now.Zone()
+		shift := time.Duration(offset) * time.Second
+		r.next = now.Add(shift).Truncate(r.Interval).Add(r.Interval).Add(-shift)
+		return
+	}
+	y, m, d := now.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	next := midnight.Add(now.Sub(midnight).Truncate(r.Interval) + r.Interval)
+	// Days with daylight saving changes don't have 24 hours.
+	if tomorrow := time.Date(y, m, d+1, 0, 0, 0, 0, now.Location()); next.After(tomorrow) {
+		next = tomorrow
+	}
+	r.next = next
+}
+
+// Reopen closes and opens again the log file.
+func (r *RotatingFile) Reopen() error {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+	return r.reopen()
+}
+
+func (r *RotatingFile) reopen() error {
+	if r.file != nil {
+		err := r.file.Close()
+		r.file = nil
+		if err != nil {
+			return e.Forward(err)
+		}
+	}
+	return r.open()
+}
+
+// Write writes p to the file, rotating the file first if needed.
+func (r *RotatingFile) Write(p []byte) (n int, err error) {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+	if r.file == nil {
+		err = r.open()
+		if err != nil {
+			return 0, err
+		}
+	}
+	now := time.Now()
+	if (r.MaxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.MaxSize) ||
+		(r.Interval > 0 && !now.Before(r.next)) {
+		err = r.rotate(now)
+		if err != nil {
+			return 0, err
+		}
+	}
+	n, err = r.file.Write(p)
+	r.size += int64(n)
+	if err != nil {
+		return n, e.Forward(err)
+	}
+	return n, nil
+}
+
+// Rotate closes the current file, moves it to a backup and opens a new file.
+func (r *RotatingFile) Rotate() error {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+	return r.rotate(time.Now())
+}
+
+func (r *RotatingFile) rotate(now time.Time) error {
+	if r.file != nil {
+		err := r.file.Close()
+		r.file = nil
+		if err != nil {
+			return e.Forward(err)
+		}
+	}
+	var backup string
+	var err error
+	switch r.Naming {
+	case TimestampBackups:
+		backup, err = r.rotateTimestamp(now)
+	default:
+		backup, err = r.rotateNumbered()
+	}
+	if err != nil {
+		return err
+	}
+	if r.Compress && backup != "" {
+		err = compress(backup)
+		if err != nil {
+			return err
+		}
+	}
+	err = r.prune()
+	if err != nil {
+		return err
+	}
+	err = r.open()
+	if err != nil {
+		return err
+	}
+	r.setNext(now)
+	return nil
+}
+
+func (r *RotatingFile) numbered(i int) string {
+	return r.Filename + "." + strconv.Itoa(i)
+}
+
+func exists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
+
+// rename moves the backup from to the backup to, with or without the gzip
+// extension.
+func rename(from, to string) error {
+	if exists(from + gzExt) {
+		from += gzExt
+		to += gzExt
+	} else if !exists(from) {
+		return nil
+	}
+	err := os.Rename(from, to)
+	if err != nil {
+		return e.Forward(err)
+	}
+	return nil
+}
+
+func (r *RotatingFile) rotateNumbered() (string, error) {
+	last := 1
+	for exists(r.numbered(last)) || exists(r.numbered(last)+gzExt) {
+		last++
+	}
+	if r.MaxBackups > 0 && last > r.MaxBackups {
+		last = r.MaxBackups
+	}
+	for i := last - 1; i >= 1; i-- {
+		err := rename(r.numbered(i), r.numbered(i+1))
+		if err != nil {
+			return "", err
+		}
+	}
+	if !exists(r.Filename) {
+		return "", nil
+	}
+	backup := r.numbered(1)
+	err := os.Rename(r.Filename, backup)
+	if err != nil {
+		return "", e.Forward(err)
+	}
+	return backup, nil
+}
+
+func (r *RotatingFile) timestampParts() (prefix, ext string) {
+	ext = filepath.Ext(r.Filename)
+	prefix = strings.TrimSuffix(r.Filename, ext) + "-"
+	return
+}
+
+func (r *RotatingFile) timeLayout() string {
+	if r.TimeLayout == "" {
+		return DefaultBackupTimeLayout
+	}
+	return r.TimeLayout
+}
+
+func (r *RotatingFile) rotateTimestamp(now time.Time) (string, error) {
+	if !exists(r.Filename) {
+		return "", nil
+	}
+	prefix, ext := r.timestampParts()
+	stamp := prefix + now.Format(r.timeLayout())
+	backup := stamp + ext
+	// Two rotations in the same tick of the layout get a sequence number,
+	// like file-2006-01-02-1.log.
+	for i := 1; exists(backup) || exists(backup+gzExt); i++ {
+		backup = stamp + "-" + strconv.Itoa(i) + ext
+	}
+	err := os.Rename(r.Filename, backup)
+	if err != nil {
+		return "", e.Forward(err)
+	}
+	return backup, nil
+}
+
+type timestampBackup struct {
+	name string
+	t    time.Time
+	seq  int
+}
+
+// parseBackup parses the time and the sequence number of a backup named by
+// rotateTimestamp, ok is false if name isn't a backup of r.
+func (r *RotatingFile) parseBackup(name string) (b timestampBackup, ok bool) {
+	prefix, ext := r.timestampParts()
+	prefix = filepath.Base(prefix)
+	stamp := strings.TrimSuffix(name, gzExt)
+	if !strings.HasPrefix(stamp, prefix) || !strings.HasSuffix(stamp, ext) {
+		return b, false
+	}
+	stamp = stamp[len(prefix) : len(stamp)-len(ext)]
+	layout := r.timeLayout()
+	t, err := time.ParseInLocation(layout, stamp, time.Local)
+	if err == nil {
+		return timestampBackup{name: name, t: t}, true
+	}
+	i := strings.LastIndexByte(stamp, '-')
+	if i < 0 {
+		return b, false
+	}
+	seq, err := strconv.Atoi(stamp[i+1:])
+	if err != nil || seq < 1 {
+		return b, false
+	}
+	t, err = time.ParseInLocation(layout, stamp[:i], time.Local)
+	if err != nil {
+		return b, false
+	}
+	return timestampBackup{name: name, t: t, seq: seq}, true
+}
+
+// Backups returns the rotated files, the newest first.
+func (r *RotatingFile) Backups() ([]string, error) {
+	switch r.Naming {
+	case TimestampBackups:
+		dir := filepath.Dir(r.Filename)
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, e.Forward(err)
+		}
+		var found []timestampBackup
+		for _, f := range files {
+			if f.IsDir() {
+				continue
+			}
+			if b, ok := r.parseBackup(f.Name()); ok {
+				found = append(found, b)
+			}
+		}
+		sort.Slice(found, func(i, j int) bool {
+			if !found[i].t.Equal(found[j].t) {
+				return found[i].t.After(found[j].t)
+			}
+			return found[i].seq > found[j].seq
+		})
+		backups := make([]string, len(found))
+		for i, b := range found {
+			backups[i] = filepath.Join(dir, b.name)
+		}
+		return backups, nil
+	default:
+		var backups []string
+		for i := 1; ; i++ {
+			name := r.numbered(i)
+			if exists(name + gzExt) {
+				name += gzExt
+			} else if !exists(name) {
+				break
+			}
+			backups = append(backups, name)
+		}
+		return backups, nil
+	}
+}
+
+func (r *RotatingFile) prune() error {
+	if r.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := r.Backups()
+	if err != nil {
+		return err
+	}
+	if len(backups) <= r.MaxBackups {
+		return nil
+	}
+	for _, name := range backups[r.MaxBackups:] {
+		err = os.Remove(name)
+		if err != nil {
+			return e.Forward(err)
+		}
+	}
+	return nil
+}
+
+func compress(name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return e.Forward(err)
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return e.Forward(err)
+	}
+	out, err := os.OpenFile(name+gzExt, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return e.Forward(err)
+	}
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if err != nil {
+		gz.Close()
+		out.Close()
+		os.Remove(name + gzExt)
+		return e.Forward(err)
+	}
+	err = gz.Close()
+	if err != nil {
+		out.Close()
+		os.Remove(name + gzExt)
+		return e.Forward(err)
+	}
+	err = out.Close()
+	if err != nil {
+		os.Remove(name + gzExt)
+		return e.Forward(err)
+	}
+	err = os.Remove(name)
+	if err != nil {
+		return e.Forward(err)
+	}
+	return nil
+}
+
+// Sync commits the content of the file to the disk.
+func (r *RotatingFile) Sync() error {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Sync()
+	if err != nil {
+		return e.Forward(err)
+	}
+	return nil
+}
+
+// Close closes the file and stops the signal handling.
+func (r *RotatingFile) Close() error {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+	if r.sig != nil {
+		signal.Stop(r.sig)
+		close(r.done)
+		r.sig = nil
+		r.done = nil
+	}
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	if err != nil {
+		return e.Forward(err)
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func readFile(t *testing.T, name string) string {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	return string(data)
+}
+
+func readGzip(t *testing.T, name string) string {
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	data, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	return string(data)
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "slog")
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	return dir
+}
+
+func TestRotatingFileSize(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	rf := &RotatingFile{
+		Filename:   filepath.Join(dir, "test.log"),
+		MaxSize:    10,
+		MaxBackups: 2,
+	}
+	defer rf.Close()
+
+	for _, s := range []string{"0123456789", "abcdefghij", "ABCDEFGHIJ", "last"} {
+		_, err := rf.Write([]byte(s))
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+	}
+
+	if s := readFile(t, rf.Filename); s != "last" {
+		t.Fatal("wrong content", s)
+	}
+	if s := readFile(t, rf.Filename+".1"); s != "ABCDEFGHIJ" {
+		t.Fatal("wrong content", s)
+	}
+	if s := readFile(t, rf.Filename+".2"); s != "abcdefghij" {
+		t.Fatal("wrong content", s)
+	}
+	if _, err := os.Stat(rf.Filename + ".3"); !os.IsNotExist(err) {
+		t.Fatal("too many backups")
+	}
+}
+
+func TestRotatingFileCompress(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	rf := &RotatingFile{
+		Filename: filepath.Join(dir, "test.log"),
+		MaxSize:  10,
+		Compress: true,
+	}
+	defer rf.Close()
+
+	for _, s := range []string{"0123456789", "abcdefghij", "last"} {
+		_, err := rf.Write([]byte(s))
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+	}
+
+	if s := readGzip(t, rf.Filename+".1.gz"); s != "abcdefghij" {
+		t.Fatal("wrong content", s)
+	}
+	if s := readGzip(t, rf.Filename+".2.gz"); s != "0123456789" {
+		t.Fatal("wrong content", s)
+	}
+	backups, err := rf.Backups()
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	if len(backups) != 2 {
+		t.Fatal("wrong number of backups", backups)
+	}
+}
+
+func TestRotatingFileTimestamp(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	rf := &RotatingFile{
+		Filename:   filepath.Join(dir, "test.log"),
+		Naming:     TimestampBackups,
+		TimeLayout: "20060102150405.000000000",
+		MaxBackups: 2,
+	}
+	defer rf.Close()
+
+	for i := 0; i < 4; i++ {
+		_, err := rf.Write([]byte("entry"))
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+		err = rf.Rotate()
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	backups, err := rf.Backups()
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	if len(backups) != 2 {
+		t.Fatal("wrong number of backups", backups)
+	}
+	for _, b := range backups {
+		name := filepath.Base(b)
+		if !strings.HasPrefix(name, "test-") || !strings.HasSuffix(name, ".log") {
+			t.Fatal("wrong backup name", name)
+		}
+	}
+	if backups[0] < backups[1] {
+		t.Fatal("backups not sorted", backups)
+	}
+}
+
+func TestRotatingFileTimestampLayout(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"app-errors.log", "app-zzz.log", "app-31-12-2099.txt"} {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte("other"), 0600)
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+	}
+	// Older than today but greater as a string.
+	old := filepath.Join(dir, "app-31-12-2017.log")
+	err := ioutil.WriteFile(old, []byte("old"), 0600)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	rf := &RotatingFile{
+		Filename:   filepath.Join(dir, "app.log"),
+		Naming:     TimestampBackups,
+		TimeLayout: "02-01-2006",
+		MaxBackups: 2,
+	}
+	defer rf.Close()
+
+	// All rotations are in the same day.
+	for i := 0; i < 3; i++ {
+		_, err = rf.Write([]byte{byte('0' + i)})
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+		err = rf.Rotate()
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+	}
+
+	backups, err := rf.Backups()
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	if len(backups) != 2 {
+		t.Fatal("wrong number of backups", backups)
+	}
+	if readFile(t, backups[0]) != "2" || readFile(t, backups[1]) != "1" {
+		t.Fatal("wrong backups", backups)
+	}
+	today := time.Now().Format("02-01-2006")
+	if filepath.Base(backups[0]) != "app-"+today+"-2.log" {
+		t.Fatal("wrong backup name", backups[0])
+	}
+	if exists(old) {
+		t.Fatal("oldest backup not removed")
+	}
+	for _, name := range []string{"app-errors.log", "app-zzz.log", "app-31-12-2099.txt"} {
+		if readFile(t, filepath.Join(dir, name)) != "other" {
+			t.Fatal("unrelated file changed", name)
+		}
+	}
+}
+
+func exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+func TestRotatingFileInterval(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	rf := &RotatingFile{
+		Filename: filepath.Join(dir, "test.log"),
+		Interval: 50 * time.Millisecond,
+	}
+	defer rf.Close()
+
+	_, err := rf.Write([]byte("first"))
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	time.Sleep(60 * time.Millisecond)
+	_, err = rf.Write([]byte("second"))
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	if s := readFile(t, rf.Filename); s != "second" {
+		t.Fatal("wrong content", s)
+	}
+	if s := readFile(t, rf.Filename+".1"); s != "first" {
+		t.Fatal("wrong content", s)
+	}
+}
+
+func TestRotatingFileReopen(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	rf := &RotatingFile{
+		Filename:    filepath.Join(dir, "test.log"),
+		ReopenOnHUP: true,
+	}
+	defer rf.Close()
+
+	_, err := rf.Write([]byte("first"))
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	// Simulate logrotate.
+	err = os.Rename(rf.Filename, rf.Filename+".old")
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	err = rf.Reopen()
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	_, err = rf.Write([]byte("second"))
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	if s := readFile(t, rf.Filename); s != "second" {
+		t.Fatal("wrong content", s)
+	}
+	if s := readFile(t, rf.Filename+".old"); s != "first" {
+		t.Fatal("wrong content", s)
+	}
+}
+
+func TestRotatingFileSlog(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	rf := &RotatingFile{
+		Filename: filepath.Join(dir, "test.log"),
+	}
+	logger := &Slog{
+		Writter: rf,
+		Level:   DebugPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	logger.Println(msg)
+	err = logger.Close()
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	if s := readFile(t, rf.Filename); !strings.HasSuffix(s, " - info - benchmark log test\n") {
+		t.Fatal("wrong content", s)
+	}
+}
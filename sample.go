@@ -0,0 +1,256 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SampleBy defines how Sampler groups the entries.
+type SampleBy uint8
+
+const (
+	// SampleCaller groups the entries by the line that logged them.
+	SampleCaller SampleBy = iota
+	// SampleTemplate groups the entries by the format string of Printf like
+	// methods or by the message of the others.
+	SampleTemplate
+)
+
+// DefaultSampleInterval is the interval used if Sampler.Interval is zero.
+var DefaultSampleInterval = time.Second
+
+// DefaultSampleFirst is the number of entries that pass in each interval if
+// Sampler.First is zero.
+var DefaultSampleFirst = 100
+
+// sampleReportKeys is the maximum number of groups listed in the summary.
+const sampleReportKeys = 5
+
+// sampleReportLen is the maximum length of a template in the summary.
+const sampleReportLen = 40
+
+// Sampler is a filter that limits the entries of hot loops. In each Interval
+// the First entries of each group pass, then one of every Thereafter entries
+// pass. When an interval with suppressed entries ends, the next entry is
+// preceded by a summary entry, tagged "sampler", with the number of
+// suppressed entries and the groups with more suppressions. The summary has
+// the priority of the highest suppressed entry and doesn't pass by the
+// filter. Use the Filter method as the Slog Filter:
+//
+//	sampler := &slog.Sampler{First: 10, Thereafter: 100}
+//	logger := &slog.Slog{Filter: sampler.Filter}
+type Sampler struct {
+	// Interval is the duration of the window.
+	Interval time.Duration
+	// First is the number of entries of each group that pass in each
+	// interval.
+	First int
+	// Thereafter is the rate of entries that pass after the First, zero
+	// drops all of them.
+	Thereafter int
+	// By is how the entries are grouped.
+	By SampleBy
+	// Level is the priority of the entries that are never sampled, zero
+	// samples all entries.
+	Level Level
+	// Skip is the number of stack frames between the Slog and Filter, set
+	// it when Filter is called by other filter function, or by a Sink,
+	// so SampleCaller finds the right line.
+	Skip int
+
+	mu         sync.Mutex
+	end        time.Time
+	counts     map[sampleKey]*sampleCount
+	prio       Level
+	suppressed uint64
+}
+
+type sampleKey struct {
+	pc  uintptr
+	str string
+}
+
+type sampleCount struct {
+	n          uint64
+	suppressed uint64
+}
+
+type sampleGroup struct {
+	key        sampleKey
+	suppressed uint64
+}
+
+type sampleReport struct {
+	total  uint64
+	prio   Level
+	groups []sampleGroup
+}
+
+func (s *Sampler) key(sl *Slog) sampleKey {
+	if s.By == SampleTemplate {
+		if sl.Log.tmpl != "" {
+			return sampleKey{str: sl.Log.tmpl}
+		}
+		return sampleKey{str: sl.Log.msg}
+	}
+	if sl.Log.file != "" {
+		return sampleKey{str: sl.Log.file}
+	}
+	// 0 is Callers, 1 key, 2 Filter, 3 commit, like debugInfo.
+	var pc [1]uintptr
+	runtime.Callers(sl.Log.DiLevel+1+s.Skip, pc[:])
+	return sampleKey{pc: pc[0]}
+}
+
+func (s *Sampler) interval() time.Duration {
+	if s.Interval > 0 {
+		return s.Interval
+	}
+	return DefaultSampleInterval
+}
+
+func (s *Sampler) first() uint64 {
+	if s.First > 0 {
+		return uint64(s.First)
+	}
+	return uint64(DefaultSampleFirst)
+}
+
+// Filter returns true if the entry pass the sampler.
+func (s *Sampler) Filter(sl *Slog) bool {
+	if s.Level != 0 && sl.Log.Priority >= s.Level {
+		return true
+	}
+	key := s.key(sl)
+	now := sl.now()
+	first := s.first()
+
+	s.mu.Lock()
+	var report *sampleReport
+	if s.counts == nil || !now.Before(s.end) {
+		report = s.rollover(now)
+	}
+	c := s.counts[key]
+	if c == nil {
+		c = &sampleCount{}
+		s.counts[key] = c
+	}
+	c.n++
+	pass := c.n <= first
+	if !pass && s.Thereafter > 0 {
+		pass = (c.n-first)%uint64(s.Thereafter) == 0
+	}
+	if !pass {
+		c.suppressed++
+		if sl.Log.Priority > s.prio {
+			s.prio = sl.Log.Priority
+		}
+	}
+	s.mu.Unlock()
+
+	if !pass {
+		atomic.AddUint64(&s.suppressed, 1)
+	}
+	if report != nil {
+		s.report(sl, report)
+	}
+	return pass
+}
+
+// rollover starts a new interval and returns the summary of the last one, or
+// nil if nothing was suppressed.
+func (s *Sampler) rollover(now time.Time) *sampleReport {
+	var report *sampleReport
+	for key, c := range s.counts {
+		if c.suppressed == 0 {
+			continue
+		}
+		if report == nil {
+			report = &sampleReport{prio: s.prio}
+		}
+		report.total += c.suppressed
+		report.groups = append(report.groups, sampleGroup{key: key, suppressed: c.suppressed})
+	}
+	s.counts = make(map[sampleKey]*sampleCount)
+	s.end = now.Add(s.interval())
+	s.prio = 0
+	return report
+}
+
+func (k sampleKey) String() string {
+	if k.pc == 0 {
+		if len(k.str) > sampleReportLen {
+			return strconv.Quote(k.str[:sampleReportLen] + "...")
+		}
+		return strconv.Quote(k.str)
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{k.pc}).Next()
+	return fileLine(frame.File, frame.Line)
+}
+
+// report commits the summary of the suppressed entries with the committer of
+// sl.
+func (s *Sampler) report(sl *Slog, report *sampleReport) {
+	sort.Slice(report.groups, func(i, j int) bool {
+		a, b := report.groups[i], report.groups[j]
+		if a.suppressed != b.suppressed {
+			return a.suppressed > b.suppressed
+		}
+		if a.key.pc != b.key.pc {
+			return a.key.pc < b.key.pc
+		}
+		return a.key.str < b.key.str
+	})
+	msg := make([]byte, 0, 128)
+	msg = strconv.AppendUint(msg, report.total, 10)
+	msg = append(msg, " entries suppressed by the sampler in "...)
+	msg = append(msg, s.interval().String()...)
+	msg = append(msg, ':')
+	for i, g := range report.groups {
+		if i == sampleReportKeys {
+			msg = append(msg, " ..."...)
+			break
+		}
+		if i > 0 {
+			msg = append(msg, ',')
+		}
+		msg = append(msg, ' ')
+		msg = append(msg, g.key.String()...)
+		msg = append(msg, " ("...)
+		msg = strconv.AppendUint(msg, g.suppressed, 10)
+		msg = append(msg, ')')
+	}
+
+	notice(sl, report.prio, "sampler", report.total, string(msg))
+}
+
+// notice commits, with the committer of sl and without filtering, an entry
+// that tells how many entries a filter suppressed.
+func notice(sl *Slog, prio Level, tag string, suppressed uint64, msg string) {
+	n := sl.dup()
+	n.Cp = true
+	n.Log.Priority = prio
+	n.Log.DoDi = false
+	n.Log.file = ""
+	n.Log.Tags = newTags(1)
+	n.Log.Tags.Add(tag)
+	n.Log.Fields = newFields(1)
+	n.Log.Fields.Add(Field{Key: "suppressed", Value: suppressed})
+	n.Log.Message(msg)
+	n.Commit(n)
+	n.release()
+}
+
+// Suppressed returns the number of entries suppressed since the sampler was
+// created.
+func (s *Sampler) Suppressed() uint64 {
+	return atomic.LoadUint64(&s.suppressed)
+}
@@ -0,0 +1,141 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/fcavani/slog"
+	"github.com/fcavani/slog/slogtest"
+)
+
+// newFakeClockLogger initializes logger in domain, with level debug, a buffer
+// as writer and a fake clock. The fields that the test is about, like Filter
+// or Commit, are set by the caller.
+func newFakeClockLogger(t *testing.T, logger *Slog, domain string) (*Slog, *writerCloser, *slogtest.FakeClock) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+	clock := slogtest.NewFakeClock(time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC))
+	logger.Level = DebugPrio
+	logger.Writter = buf
+	logger.Clock = clock
+	err := logger.Init(domain, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return logger, buf, clock
+}
+
+func lines(buf *writerCloser) []string {
+	s := strings.TrimSuffix(buf.String(), "\n")
+	buf.Reset()
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func TestSamplerCaller(t *testing.T) {
+	sampler := &Sampler{Interval: time.Second, First: 2, Thereafter: 3}
+	logger, buf, clock := newFakeClockLogger(t, &Slog{Filter: sampler.Filter}, "teste")
+
+	for i := 0; i < 10; i++ {
+		logger.DebugLevel().Printf("loop %v", i)
+		logger.Print("other line")
+	}
+	l := lines(buf)
+	// 1, 2, 5 and 8 of each line pass.
+	if len(l) != 8 {
+		t.Fatal("wrong number of entries", len(l), l)
+	}
+	if !strings.HasSuffix(l[4], "loop 4") || !strings.HasSuffix(l[6], "loop 7") {
+		t.Fatal("wrong entries", l)
+	}
+	if sampler.Suppressed() != 12 {
+		t.Fatal("wrong suppressed", sampler.Suppressed())
+	}
+
+	clock.Add(time.Second)
+	logger.Print(msg)
+	l = lines(buf)
+	if len(l) != 2 {
+		t.Fatal("wrong number of entries", l)
+	}
+	expected := " - info - sampler - suppressed=12 - 12 entries suppressed by the sampler in 1s: slog/sample_test.go:48 (6), slog/sample_test.go:49 (6)"
+	if !strings.HasSuffix(l[0], expected) {
+		t.Fatalf("wrong summary %q", l[0])
+	}
+	if !strings.HasSuffix(l[1], msg) {
+		t.Fatalf("wrong entry %q", l[1])
+	}
+
+	clock.Add(time.Second)
+	logger.Print(msg)
+	l = lines(buf)
+	if len(l) != 1 {
+		t.Fatal("summary without suppressed entries", l)
+	}
+}
+
+func TestSamplerTemplate(t *testing.T) {
+	sampler := &Sampler{First: 1, By: SampleTemplate, Level: ErrorPrio}
+	logger, buf, clock := newFakeClockLogger(t, &Slog{Filter: sampler.Filter}, "teste")
+
+	for i := 0; i < 3; i++ {
+		logger.Printf("request %v", i)
+		logger.Warnf("request %v", i)
+		logger.Error("always logged")
+	}
+	l := lines(buf)
+	if len(l) != 4 {
+		t.Fatal("wrong number of entries", l)
+	}
+	if !strings.HasSuffix(l[0], "request 0") {
+		t.Fatal("wrong entries", l)
+	}
+	for _, line := range l[1:] {
+		if !strings.HasSuffix(line, "always logged") {
+			t.Fatal("wrong entries", l)
+		}
+	}
+
+	clock.Add(DefaultSampleInterval)
+	logger.Print(strings.Repeat("x", 50))
+	l = lines(buf)
+	if len(l) != 2 {
+		t.Fatal("wrong number of entries", l)
+	}
+	expected := " - warning - sampler - suppressed=5 - 5 entries suppressed by the sampler in 1s: \"request %v\" (5)"
+	if !strings.HasSuffix(l[0], expected) {
+		t.Fatalf("wrong summary %q", l[0])
+	}
+}
+
+func TestSamplerConcurrent(t *testing.T) {
+	sampler := &Sampler{First: 10}
+	logger, buf, _ := newFakeClockLogger(t, &Slog{Filter: sampler.Filter}, "teste")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Print(msg)
+			}
+		}()
+	}
+	wg.Wait()
+	l := lines(buf)
+	if len(l) != 10 {
+		t.Fatal("wrong number of entries", len(l))
+	}
+	if sampler.Suppressed() != 390 {
+		t.Fatal("wrong suppressed", sampler.Suppressed())
+	}
+}
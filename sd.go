@@ -8,7 +8,6 @@ import (
 	"os"
 	"runtime"
 	"strconv"
-	"time"
 
 	"github.com/fcavani/slog/systemd"
 )
@@ -22,6 +21,8 @@ func Prior2Sd(level Level) systemd.Priority {
 		return systemd.PriDebug
 	case InfoPrio:
 		return systemd.PriInfo
+	case WarnPrio:
+		return systemd.PriWarning
 	case ErrorPrio:
 		return systemd.PriErr
 	case FatalPrio:
@@ -37,7 +38,7 @@ func Prior2Sd(level Level) systemd.Priority {
 
 // CommitSd send to systemd journal the log entry.
 func CommitSd(sl *Slog) {
-	sl.Log.Timestamp = time.Now()
+	sl.stamp()
 
 	if systemd.Enabled() || testing {
 		buf, err := sl.Formatter(sl)
@@ -74,6 +75,28 @@ func CommitSd(sl *Slog) {
 			vars["TAGS"] = sl.Log.Tags.String()
 		}
 
+		// The fields named like the journal fields written by slog or by
+		// systemd.Send are prefixed with FIELD_.
+		fvars := make(map[string]string, len(*sl.Log.Fields))
+		for _, f := range *sl.Log.Fields {
+			key := journalField(f.Key)
+			if key == "" {
+				continue
+			}
+			if _, found := vars[key]; found || key == "MESSAGE" || key == "PRIORITY" {
+				key = "FIELD_" + key
+			}
+			if _, found := fvars[key]; found {
+				continue
+			}
+			var val []byte
+			appendTextValue(&val, f.Value)
+			fvars[key] = string(val)
+		}
+		for key, val := range fvars {
+			vars[key] = val
+		}
+
 		err = sendToSd(string(buf), Prior2Sd(sl.Log.Priority), vars)
 		if err != nil {
 			println("SLOG writer failed:", err)
@@ -86,7 +109,7 @@ func CommitSd(sl *Slog) {
 	// Fallback formatter and commiter.
 	// Send the log to some file normally the os.Stdout.
 	// Set slog Writter property to os.Stdout.
-	if sl.Log.DoDi {
+	if sl.Log.DoDi && sl.Log.file == "" {
 		sl.Log.file = debugInfo(sl.Log.DiLevel)
 	}
 
@@ -119,7 +142,7 @@ func FallbackFormater(sl *Slog) ([]byte, error) {
 	buf := Pool.Get().([]byte)
 	buf = append(buf, sl.Log.Domain...)
 	buf = append(buf, sep...)
-	FormatTime(&buf, sl.Log.Timestamp)
+	sl.appendTime(&buf, TimeLegacy)
 	buf = append(buf, sep...)
 	buf = append(buf, sl.Log.Priority.Byte()...)
 	buf = append(buf, sep...)
@@ -127,6 +150,10 @@ func FallbackFormater(sl *Slog) ([]byte, error) {
 		buf = append(buf, []byte(sl.Log.Tags.String())...)
 		buf = append(buf, sepTags...)
 	}
+	if len(*sl.Log.Fields) > 0 {
+		sl.Log.Fields.encodeText(&buf)
+		buf = append(buf, sepTags...)
+	}
 	if sl.Log.DoDi {
 		buf = append(buf, []byte(sl.Log.file)...)
 		buf = append(buf, sep...)
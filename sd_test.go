@@ -6,6 +6,9 @@ package slog_test
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/fcavani/e"
@@ -29,7 +32,7 @@ func TestSdPrint(t *testing.T) {
 	logger = logger.Di().MakeDefault()
 
 	logger.Tag("tag1", "tag2").Println(msg)
-	AssertLine(t, buf, "teste - info - tag1 tag2 - slog/sd_test.go:31 - benchmark log test")
+	AssertLine(t, buf, "teste - info - tag1 tag2 - slog/sd_test.go:34 - benchmark log test")
 
 	Testing(true)
 
@@ -38,3 +41,62 @@ func TestSdPrint(t *testing.T) {
 	logger.InfoLevel().Tag("tag1", "tag2").Println(msg)
 	logger.ErrorLevel().Tag("tag1", "tag2").Println(msg)
 }
+
+func TestSdFields(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter:   buf,
+		Level:     ProtoPrio,
+		Commit:    CommitSd,
+		Formatter: SdFormater,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	Testing(false)
+	logger.With("user", "john").Println(msg)
+	AssertLine(t, buf, "teste - info - user=john - benchmark log test")
+
+	Testing(true)
+	defer Testing(false)
+	logger.With("user", "john", "_trusted", "no").Println(msg)
+
+	// The mock journal writes in os.Stdout.
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	Testing(true)
+	os.Stdout = stdout
+	logger.With("message", "spoof", "priority", 0, "domain", "other").Println(msg)
+	w.Close()
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := make(map[string][]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if i := strings.IndexByte(line, '='); i > 0 {
+			vars[line[:i]] = append(vars[line[:i]], line[i+1:])
+		}
+	}
+	for key, val := range map[string]string{
+		"PRIORITY":       "6",
+		"DOMAIN":         "teste",
+		"FIELD_MESSAGE":  "spoof",
+		"FIELD_PRIORITY": "0",
+		"FIELD_DOMAIN":   "other",
+	} {
+		if len(vars[key]) != 1 || vars[key][0] != val {
+			t.Fatalf("wrong journal field %v: %v", key, vars[key])
+		}
+	}
+	if len(vars["MESSAGE"]) != 0 {
+		t.Fatalf("message replaced by field: %v", vars["MESSAGE"])
+	}
+}
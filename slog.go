@@ -7,7 +7,6 @@ package slog
 import (
 	"fmt"
 	"io"
-	"math"
 	"os"
 	"runtime"
 	"strconv"
@@ -21,6 +20,7 @@ import (
 
 const (
 	numTags        = 5
+	numFields      = 5
 	fnLevelDi      = 4
 	fnLevelDiPlus1 = 5
 )
@@ -48,6 +48,7 @@ const (
 	ProtoPrio Level = iota + 1 //More priority
 	DebugPrio
 	InfoPrio
+	WarnPrio
 	ErrorPrio
 	FatalPrio
 	PanicPrio
@@ -57,6 +58,7 @@ const (
 var protoPrio = []byte("protocol")
 var debugPrio = []byte("debug")
 var infoPrio = []byte("info")
+var warnPrio = []byte("warning")
 var errorPrio = []byte("error")
 var fatalPrio = []byte("fatal")
 var panicPrio = []byte("panic")
@@ -71,6 +73,8 @@ func (l Level) Byte() []byte {
 		return debugPrio
 	case InfoPrio:
 		return infoPrio
+	case WarnPrio:
+		return warnPrio
 	case ErrorPrio:
 		return errorPrio
 	case FatalPrio:
@@ -93,6 +97,8 @@ func (l Level) String() string {
 		return "debug"
 	case InfoPrio:
 		return "info"
+	case WarnPrio:
+		return "warning"
 	case ErrorPrio:
 		return "error"
 	case FatalPrio:
@@ -115,6 +121,8 @@ func (l Level) Color(au aurora.Aurora) func(interface{}) aurora.Value {
 		return au.Bold
 	case InfoPrio:
 		return au.Green
+	case WarnPrio:
+		return au.Brown
 	case ErrorPrio:
 		return au.Red
 	case FatalPrio:
@@ -137,6 +145,8 @@ func ParseLevel(level string) (Level, error) {
 		return DebugPrio, nil
 	case "info":
 		return InfoPrio, nil
+	case "warning", "warn":
+		return WarnPrio, nil
 	case "error":
 		return ErrorPrio, nil
 	case "fatal":
@@ -157,13 +167,11 @@ type Log struct {
 	Priority  Level
 	Timestamp time.Time
 	Tags      *tags
+	Fields    *fields
 	msg       string
+	tmpl      string
 	DiLevel   int
 	DoDi      bool
-	zoneHour  int
-	zoneMin   int
-	zoneSig   bool
-	zoneBuf   []byte
 	file      string
 }
 
@@ -208,11 +216,12 @@ func (l *Log) FormatMessage() string {
 
 // String print the Log struct contents.
 func (l *Log) String() string {
-	return fmt.Sprintf("Domain: %v\nPriority: %v\nTimestamp: %v\nTags: %v\nMessage: %v\n",
+	return fmt.Sprintf("Domain: %v\nPriority: %v\nTimestamp: %v\nTags: %v\nFields: %v\nMessage: %v\n",
 		string(l.Domain),
 		l.Priority.String(),
 		l.Timestamp.Format(time.RFC3339Nano),
 		l.Tags.String(),
+		l.Fields.String(),
 		l.FormatMessage(),
 	)
 }
@@ -225,60 +234,58 @@ func (l *Log) copy() *Log {
 		Priority:  l.Priority,
 		Timestamp: l.Timestamp,
 		Tags:      l.Tags.copy(),
+		Fields:    l.Fields.copy(),
 		msg:       l.msg,
 		DiLevel:   l.DiLevel,
 		DoDi:      l.DoDi,
-		zoneHour:  l.zoneHour,
-		zoneMin:   l.zoneMin,
-		zoneSig:   l.zoneSig,
-		zoneBuf:   l.zoneBuf,
 	}
 }
 
+// copyTo copies l to dst like copy, but reusing the tags and fields of dst.
+// The domain is shared, it's never changed in place.
+func (l *Log) copyTo(dst *Log) {
+	dst.Domain = l.Domain
+	dst.Priority = l.Priority
+	dst.Timestamp = l.Timestamp
+	if dst.Tags == nil {
+		dst.Tags = newTags(numTags)
+	}
+	l.Tags.copyTo(dst.Tags)
+	if dst.Fields == nil {
+		dst.Fields = newFields(numFields)
+	}
+	l.Fields.copyTo(dst.Fields)
+	dst.msg = l.msg
+	dst.DiLevel = l.DiLevel
+	dst.DoDi = l.DoDi
+}
+
 // debugInfo populates the Log struct with the debug information.
 func debugInfo(level int) (file string) {
 	var ok bool
 	var line int
 	_, file, line, ok = runtime.Caller(level)
 	if ok {
-		s := strings.Split(file, "/")
-		length := len(s)
-		if length >= 2 {
-			file = strings.Join(s[length-2:length], "/") + ":" + strconv.Itoa(line)
-		} else {
-			file = s[0] + ":" + strconv.Itoa(line)
-		}
+		file = fileLine(file, line)
 	}
 	return
 }
 
-func timeZone() (h, m int, sig bool) {
-	t := time.Now()
-	_, offset := t.Zone() //offset is secods East UTC
-	if offset >= 0 {
-		sig = true
-	} else {
-		offset = -offset
+// fileLine returns the last directory, the file name and the line.
+func fileLine(file string, line int) string {
+	s := strings.Split(file, "/")
+	length := len(s)
+	if length >= 2 {
+		return strings.Join(s[length-2:length], "/") + ":" + strconv.Itoa(line)
 	}
-	h = int(math.Floor(float64(offset) / 3600.0))
-	m = int(math.Floor(float64(offset%3600) / 60.0))
-	return
+	return s[0] + ":" + strconv.Itoa(line)
 }
 
-// SetTimeZone sets the zone info from time.Now()
-func (l *Log) SetTimeZone() {
-	l.zoneHour, l.zoneMin, l.zoneSig = timeZone()
-	l.zoneBuf = make([]byte, 0, 6)
-	if l.zoneSig {
-		// TODO: check if UTC is + or -. Check if East UTC is really + before the offset.
-		l.zoneBuf = append(l.zoneBuf, '+')
-	} else {
-		l.zoneBuf = append(l.zoneBuf, '-')
-	}
-	Itoa(&l.zoneBuf, l.zoneHour, 2)
-	l.zoneBuf = append(l.zoneBuf, ':')
-	Itoa(&l.zoneBuf, l.zoneMin, 2)
-}
+// SetTimeZone does nothing, the zone offset is derived from the Timestamp of
+// each entry.
+//
+// Deprecated: the zone doesn't need to be set.
+func (l *Log) SetTimeZone() {}
 
 func (l *Log) di(deep int) {
 	if l.DiLevel >= deep {
@@ -289,7 +296,8 @@ func (l *Log) di(deep int) {
 
 // Slog is the logger.
 type Slog struct {
-	// Level is the max log level that will filter the entries.
+	// Level is the max log level that will filter the entries. After Init
+	// use ChangeLevel to change it.
 	Level Level
 	// Filter filter the log entry. If return true the log entry pass the filter.
 	Filter func(l *Slog) bool
@@ -301,8 +309,19 @@ type Slog struct {
 	Writter io.WriteCloser
 	// Log entry.
 	Log *Log
-	// Exiter is the function called on Fatal and Panic methods.
+	// Time is the format of the timestamp.
+	Time TimeFormat
+	// Clock gives the timestamp of the entries, the default is RealClock.
+	Clock Clock
+	// Exiter is the function called on Fatal and Panic methods. After Init
+	// use ChangeExiter to change it.
 	Exiter func(int)
+	// Flusher is called by Flush to drain a committer that buffers the
+	// entries, like Async.
+	Flusher Flusher
+	// FlushTimeout is the maximum time Flush waits, if zero
+	// DefaultFlushTimeout is used.
+	FlushTimeout time.Duration
 	// Enable coloring of the log entry.
 	colors  bool
 	au      aurora.Aurora
@@ -310,8 +329,35 @@ type Slog struct {
 	once    sync.Once
 	Lck     *sync.Mutex
 	Cp      bool
-	wbuf    []byte
-	wlck    sync.Mutex
+	cfg     *config
+	// helper is called by the functions between the caller and the
+	// committer, see NewTestLogger.
+	helper func()
+	wbuf   []byte
+	wlck   sync.Mutex
+}
+
+// commitWriter is the default committer, it writes the entry in the Writter
+// with the Formatter.
+func commitWriter(sl *Slog) {
+	sl.helper()
+	sl.stamp()
+	if sl.Log.DoDi && sl.Log.file == "" {
+		sl.Log.file = debugInfo(sl.Log.DiLevel)
+	}
+	buf, err := sl.Formatter(sl)
+	if err != nil {
+		//TODO: Give to the user a nice error message.
+		println("SLOG writer failed:", err)
+		return
+	}
+	sl.Lck.Lock()
+	_, err = sl.Writter.Write(buf)
+	if err != nil {
+		println("SLOG writer failed:", err)
+	}
+	Pool.Put(buf[:0])
+	sl.Lck.Unlock()
 }
 
 // Itoa converts a int to a byte. i is the interger to be converted, buf is a pointer
@@ -335,20 +381,9 @@ func Itoa(buf *[]byte, i int, wid int) {
 
 // FormatTime is a sample function for format the data and time.
 func FormatTime(buf *[]byte, t time.Time) {
-	// TODO: Time zone and UTC
-	year, month, day := t.Date()
-	Itoa(buf, year, 4)
-	*buf = append(*buf, '/')
-	Itoa(buf, int(month), 2)
-	*buf = append(*buf, '/')
-	Itoa(buf, day, 2)
+	appendDate(buf, t, '/')
 	*buf = append(*buf, ' ')
-	hour, min, sec := t.Clock()
-	Itoa(buf, hour, 2)
-	*buf = append(*buf, ':')
-	Itoa(buf, min, 2)
-	*buf = append(*buf, ':')
-	Itoa(buf, sec, 2)
+	appendClock(buf, t)
 }
 
 var sep = []byte(" - ")
@@ -378,6 +413,7 @@ func (l *Slog) Init(domain string, nl int) error {
 		l.Log = &Log{
 			Priority: InfoPrio,
 			Tags:     newTags(numTags),
+			Fields:   newFields(numFields),
 			DiLevel:  fnLevelDi,
 			DoDi:     false,
 		}
@@ -393,6 +429,9 @@ func (l *Slog) Init(domain string, nl int) error {
 	if l.Log.Tags == nil {
 		l.Log.Tags = newTags(numTags)
 	}
+	if l.Log.Fields == nil {
+		l.Log.Fields = newFields(numFields)
+	}
 	if len(l.Log.Domain) == 0 {
 		l.Log.Domain = []byte("Slog")
 	}
@@ -403,14 +442,12 @@ func (l *Slog) Init(domain string, nl int) error {
 		l.Lck = new(sync.Mutex)
 	}
 
-	l.Log.SetTimeZone()
-
 	if l.Formatter == nil {
 		l.Formatter = func(sl *Slog) ([]byte, error) {
 			buf := Pool.Get().([]byte)
 			buf = append(buf, sl.Log.Domain...)
 			buf = append(buf, sep...)
-			FormatTime(&buf, sl.Log.Timestamp)
+			sl.appendTime(&buf, TimeLegacy)
 			buf = append(buf, sep...)
 			buf = append(buf, sl.Log.Priority.Byte()...)
 			buf = append(buf, sep...)
@@ -418,6 +455,10 @@ func (l *Slog) Init(domain string, nl int) error {
 				buf = append(buf, []byte(sl.Log.Tags.String())...)
 				buf = append(buf, sepTags...)
 			}
+			if len(*sl.Log.Fields) > 0 {
+				sl.Log.Fields.encodeText(&buf)
+				buf = append(buf, sepTags...)
+			}
 			if sl.Log.DoDi {
 				buf = append(buf, []byte(sl.Log.file)...)
 				buf = append(buf, sep...)
@@ -427,29 +468,17 @@ func (l *Slog) Init(domain string, nl int) error {
 		}
 	}
 	if l.Commit == nil {
-		l.Commit = func(sl *Slog) {
-			sl.Log.Timestamp = time.Now()
-			if sl.Log.DoDi {
-				sl.Log.file = debugInfo(sl.Log.DiLevel)
-			}
-			buf, err := sl.Formatter(sl)
-			if err != nil {
-				//TODO: Give to the user a nice error message.
-				println("SLOG writer failed:", err)
-				return
-			}
-			sl.Lck.Lock()
-			_, err = sl.Writter.Write(buf)
-			if err != nil {
-				println("SLOG writer failed:", err)
-			}
-			Pool.Put(buf[:0])
-			sl.Lck.Unlock()
-		}
+		l.Commit = commitWriter
 	}
 	if l.Writter == nil {
 		l.Writter = os.Stdout
 	}
+	if l.Clock == nil {
+		l.Clock = RealClock
+	}
+	if l.helper == nil {
+		l.helper = noHelper
+	}
 	if l.Exiter == nil {
 		l.Exiter = os.Exit
 	}
@@ -458,9 +487,18 @@ func (l *Slog) Init(domain string, nl int) error {
 			return true
 		}
 	}
+	if l.Level == 0 {
+		l.Level = defaultLevel()
+	}
 	if l.Level == 0 {
 		l.Level = InfoPrio
 	}
+	if l.cfg == nil {
+		l.cfg = newConfig(l.Level, l.Exiter)
+	} else {
+		l.cfg.setLevel(l.Level)
+		l.cfg.setExiter(l.Exiter)
+	}
 	l.once.Do(func() {
 		l.logPool = new(sync.Pool)
 		l.logPool.New = func() interface{} {
@@ -468,21 +506,27 @@ func (l *Slog) Init(domain string, nl int) error {
 				Domain:   l.Log.Domain,
 				Priority: InfoPrio,
 				Tags:     newTags(numTags),
+				Fields:   newFields(numFields),
 				DoDi:     false,
 				DiLevel:  0,
 			}
-			newLog.SetTimeZone()
 			sl := &Slog{
-				Level:     l.Level,
-				Formatter: l.Formatter,
-				Commit:    l.Commit,
-				Writter:   l.Writter,
-				Exiter:    l.Exiter,
-				Filter:    l.Filter,
-				Log:       newLog,
-				Lck:       l.Lck,
-				logPool:   l.logPool,
-				colors:    l.colors,
+				Level:        l.Level,
+				Formatter:    l.Formatter,
+				Commit:       l.Commit,
+				Writter:      l.Writter,
+				Exiter:       l.Exiter,
+				Filter:       l.Filter,
+				Flusher:      l.Flusher,
+				FlushTimeout: l.FlushTimeout,
+				Time:         l.Time,
+				Clock:        l.Clock,
+				Log:          newLog,
+				Lck:          l.Lck,
+				logPool:      l.logPool,
+				cfg:          l.cfg,
+				helper:       l.helper,
+				colors:       l.colors,
 			}
 			sl.au = aurora.NewAurora(sl.colors)
 			return sl
@@ -492,21 +536,27 @@ func (l *Slog) Init(domain string, nl int) error {
 				Domain:   l.Log.Domain,
 				Priority: InfoPrio,
 				Tags:     newTags(numTags),
+				Fields:   newFields(numFields),
 				DoDi:     false,
 				DiLevel:  0,
 			}
-			newLog.SetTimeZone()
 			sl := &Slog{
-				Level:     l.Level,
-				Formatter: l.Formatter,
-				Commit:    l.Commit,
-				Writter:   l.Writter,
-				Exiter:    l.Exiter,
-				Filter:    l.Filter,
-				Log:       newLog,
-				Lck:       l.Lck,
-				logPool:   l.logPool,
-				colors:    l.colors,
+				Level:        l.Level,
+				Formatter:    l.Formatter,
+				Commit:       l.Commit,
+				Writter:      l.Writter,
+				Exiter:       l.Exiter,
+				Filter:       l.Filter,
+				Flusher:      l.Flusher,
+				FlushTimeout: l.FlushTimeout,
+				Time:         l.Time,
+				Clock:        l.Clock,
+				Log:          newLog,
+				Lck:          l.Lck,
+				logPool:      l.logPool,
+				cfg:          l.cfg,
+				helper:       l.helper,
+				colors:       l.colors,
 			}
 			sl.au = aurora.NewAurora(sl.colors)
 			l.logPool.Put(sl)
@@ -520,8 +570,13 @@ func (l *Slog) copy() *Slog {
 		return l
 	}
 	out := l.logPool.Get().(*Slog)
-	out.Level = l.Level
-	out.Log = l.Log.copy()
+	out.cfg = l.cfg
+	out.helper = l.helper
+	out.Level = l.baseLevel()
+	if out.Log == nil {
+		out.Log = &Log{}
+	}
+	l.Log.copyTo(out.Log)
 	out.Exiter = l.Exiter
 	out.Cp = true
 	out.colors = l.colors
@@ -532,11 +587,17 @@ func (l *Slog) copy() *Slog {
 func (l *Slog) dup() *Slog {
 	out := l.logPool.Get().(*Slog)
 	out.Writter = l.Writter
-	out.Level = l.Level
+	out.cfg = l.cfg
+	out.helper = l.helper
+	out.Level = l.baseLevel()
 	out.Formatter = l.Formatter
 	out.Commit = l.Commit
 	out.Log = l.Log.copy()
 	out.Exiter = l.Exiter
+	out.Flusher = l.Flusher
+	out.FlushTimeout = l.FlushTimeout
+	out.Time = l.Time
+	out.Clock = l.Clock
 	out.colors = l.colors
 	out.au = aurora.NewAurora(l.colors)
 	return out
@@ -558,10 +619,13 @@ func (l *Slog) MakeDefault() *Slog {
 	return out
 }
 
-// SetLevel set the level to filter log entries.
+// SetLevel set the level to filter log entries. The level is set only for
+// this entry or, with MakeDefault, for the new default logger. Use ChangeLevel
+// to change the level of all loggers.
 func (l *Slog) SetLevel(level Level) *Slog {
 	l = l.copy()
 	l.Level = level
+	l.cfg = newConfig(level, l.cfg.getExiter())
 	return l
 }
 
@@ -586,6 +650,13 @@ func (l *Slog) InfoLevel() *Slog {
 	return l
 }
 
+// WarnLevel set the log level to warning
+func (l *Slog) WarnLevel() *Slog {
+	l = l.copy()
+	l.Log.Priority = WarnPrio
+	return l
+}
+
 // ErrorLevel set the log level to error
 func (l *Slog) ErrorLevel() *Slog {
 	l = l.copy()
@@ -601,6 +672,21 @@ func (l *Slog) Tag(tags ...string) *Slog {
 	return l
 }
 
+// With add fields to the log entry. keyvals is a list of alternating keys and
+// values, a Field can be used in place of a key/value pair.
+func (l *Slog) With(keyvals ...interface{}) *Slog {
+	l = l.copy()
+	l.Log.Fields.AddPairs(keyvals...)
+	return l
+}
+
+// Fields add typed fields to the log entry.
+func (l *Slog) Fields(fields ...Field) *Slog {
+	l = l.copy()
+	l.Log.Fields.Add(fields...)
+	return l
+}
+
 // Di add debug information to the log entry.
 func (l *Slog) Di() *Slog {
 	l = l.copy()
@@ -623,18 +709,51 @@ func (l *Slog) NoDi() *Slog {
 	return l
 }
 
+// release cleans the entry and puts l back in the pool. The tags and fields
+// keep their memory to be reused by the next copy.
+func (l *Slog) release() {
+	l.Log.Priority = InfoPrio
+	l.Log.Timestamp = time.Time{}
+	l.Log.DoDi = false
+	l.Log.DiLevel = 0
+	l.Log.file = ""
+	l.Log.tmpl = ""
+	l.Log.msg = ""
+	l.Log.Tags.reset()
+	l.Log.Fields.reset()
+	l.Cp = false
+	l.logPool.Put(l)
+}
+
+// Enabled returns true if an entry with priority level pass the Slog Level,
+// or the level of its domain set by SetDomainLevel.
+// Use it to avoid building entries that will be discarded:
+//
+//	if logger.Enabled(slog.DebugPrio) {
+//		logger.DebugLevel().Print(expensive())
+//	}
+func (l *Slog) Enabled(level Level) bool {
+	return level >= l.level()
+}
+
+// discard returns true if an entry with priority level will be discarded, in
+// this case l is released if it is a copy.
+func (l *Slog) discard(level Level) bool {
+	if level >= l.level() {
+		return false
+	}
+	if l.Cp {
+		l.release()
+	}
+	return true
+}
+
 func (l *Slog) commit() {
-	defer func() {
-		l.Log.Priority = InfoPrio
-		l.Log.DoDi = false
-		l.Log.DiLevel = 0
-		l.Log.Tags = newTags(numTags)
-		l.Cp = false
-		l.logPool.Put(l)
-	}()
+	l.helper()
+	defer l.release()
 
 	// If level is less than Priority discart the log entry
-	if l.Log.Priority < l.Level {
+	if l.Log.Priority < l.level() {
 		return
 	}
 
@@ -648,6 +767,10 @@ func (l *Slog) commit() {
 // Print prints a log entry to the destine, this is determined by the commit
 // function.
 func (l *Slog) Print(v ...interface{}) {
+	l.helper()
+	if l.discard(l.Log.Priority) {
+		return
+	}
 	l = l.copy()
 	l.Log.di(fnLevelDi)
 	l.Log.Message(fmt.Sprint(v...))
@@ -656,22 +779,75 @@ func (l *Slog) Print(v ...interface{}) {
 
 // Printf prints a formated log entry to the destine.
 func (l *Slog) Printf(s string, v ...interface{}) {
+	l.helper()
+	if l.discard(l.Log.Priority) {
+		return
+	}
 	l = l.copy()
 	l.Log.di(fnLevelDi)
+	l.Log.tmpl = s
 	l.Log.Message(fmt.Sprintf(s, v...))
 	l.commit()
 }
 
 // Println prints a log entry to the destine.
 func (l *Slog) Println(v ...interface{}) {
+	l.helper()
+	if l.discard(l.Log.Priority) {
+		return
+	}
+	l = l.copy()
+	l.Log.di(fnLevelDi)
+	l.Log.Message(fmt.Sprint(v...))
+	l.commit()
+}
+
+// Warn logs a warning.
+func (l *Slog) Warn(v ...interface{}) {
+	l.helper()
+	if l.discard(WarnPrio) {
+		return
+	}
+	l = l.copy()
+	l.Log.di(fnLevelDi)
+	l.Log.Priority = WarnPrio
+	l.Log.Message(fmt.Sprint(v...))
+	l.commit()
+}
+
+// Warnf logs a warning with format.
+func (l *Slog) Warnf(s string, v ...interface{}) {
+	l.helper()
+	if l.discard(WarnPrio) {
+		return
+	}
+	l = l.copy()
+	l.Log.di(fnLevelDi)
+	l.Log.Priority = WarnPrio
+	l.Log.tmpl = s
+	l.Log.Message(fmt.Sprintf(s, v...))
+	l.commit()
+}
+
+// Warnln logs a warning.
+func (l *Slog) Warnln(v ...interface{}) {
+	l.helper()
+	if l.discard(WarnPrio) {
+		return
+	}
 	l = l.copy()
 	l.Log.di(fnLevelDi)
+	l.Log.Priority = WarnPrio
 	l.Log.Message(fmt.Sprint(v...))
 	l.commit()
 }
 
 // Error logs an error.
 func (l *Slog) Error(v ...interface{}) {
+	l.helper()
+	if l.discard(ErrorPrio) {
+		return
+	}
 	l = l.copy()
 	l.Log.di(fnLevelDi)
 	l.Log.Priority = ErrorPrio
@@ -681,15 +857,24 @@ func (l *Slog) Error(v ...interface{}) {
 
 // Errorf logs an error with format.
 func (l *Slog) Errorf(s string, v ...interface{}) {
+	l.helper()
+	if l.discard(ErrorPrio) {
+		return
+	}
 	l = l.copy()
 	l.Log.di(fnLevelDi)
 	l.Log.Priority = ErrorPrio
+	l.Log.tmpl = s
 	l.Log.Message(fmt.Sprintf(s, v...))
 	l.commit()
 }
 
 // Errorln logs an error.
 func (l *Slog) Errorln(v ...interface{}) {
+	l.helper()
+	if l.discard(ErrorPrio) {
+		return
+	}
 	l = l.copy()
 	l.Log.di(fnLevelDi)
 	l.Log.Priority = ErrorPrio
@@ -697,77 +882,121 @@ func (l *Slog) Errorln(v ...interface{}) {
 	l.commit()
 }
 
+// Lazy prints the message returned by fn with priority level. fn is called
+// only if the entry pass the Slog Level.
+func (l *Slog) Lazy(level Level, fn func() string) {
+	l.helper()
+	if l.discard(level) {
+		return
+	}
+	l = l.copy()
+	l.Log.di(fnLevelDi)
+	l.Log.Priority = level
+	l.Log.Message(fn())
+	l.commit()
+}
+
+// Lazyf prints a formated log entry with priority level. The message is
+// formated only if the entry pass the Slog Level.
+func (l *Slog) Lazyf(level Level, s string, v ...interface{}) {
+	l.helper()
+	if l.discard(level) {
+		return
+	}
+	l = l.copy()
+	l.Log.di(fnLevelDi)
+	l.Log.Priority = level
+	l.Log.tmpl = s
+	l.Log.Message(fmt.Sprintf(s, v...))
+	l.commit()
+}
+
 // Fatal print a log entry to the destine and exit with 1.
 func (l *Slog) Fatal(v ...interface{}) {
+	l.helper()
 	l = l.copy()
 	l.Log.di(fnLevelDi)
 	l.Log.Message(fmt.Sprint(v...))
 	l.Log.Priority = FatalPrio
+	end := l.ending()
 	l.commit()
-	l.Writter.Close()
-	l.Exiter(1)
+	end.shutdown()
+	end.exit(1)
 }
 
 // Fatalf print a formated log entry to the destine and exit with 1.
 func (l *Slog) Fatalf(s string, v ...interface{}) {
+	l.helper()
 	l = l.copy()
 	l.Log.di(fnLevelDi)
+	l.Log.tmpl = s
 	l.Log.Message(fmt.Sprintf(s, v...))
 	l.Log.Priority = FatalPrio
+	end := l.ending()
 	l.commit()
-	l.Writter.Close()
-	l.Exiter(1)
+	end.shutdown()
+	end.exit(1)
 }
 
 // Fatalln print a log entry to the destine and exit with 1.
 func (l *Slog) Fatalln(v ...interface{}) {
+	l.helper()
 	l = l.copy()
 	l.Log.di(fnLevelDi)
 	l.Log.Message(fmt.Sprint(v...))
 	l.Log.Priority = FatalPrio
+	end := l.ending()
 	l.commit()
-	l.Writter.Close()
-	l.Exiter(1)
+	end.shutdown()
+	end.exit(1)
 }
 
 // Panic print a log entry to the destine and call panic.
 func (l *Slog) Panic(v ...interface{}) {
+	l.helper()
 	l = l.copy()
 	l.Log.di(fnLevelDi)
 	msg := fmt.Sprint(v...)
 	l.Log.Message(msg)
 	l.Log.Priority = PanicPrio
+	end := l.ending()
 	l.commit()
-	l.Writter.Close()
+	end.shutdown()
 	panic(msg)
 }
 
 // Panicf print a formated log entry to the destine and call panic.
 func (l *Slog) Panicf(s string, v ...interface{}) {
+	l.helper()
 	l = l.copy()
 	l.Log.di(fnLevelDi)
 	msg := fmt.Sprintf(s, v...)
+	l.Log.tmpl = s
 	l.Log.Message(msg)
 	l.Log.Priority = PanicPrio
+	end := l.ending()
 	l.commit()
-	l.Writter.Close()
+	end.shutdown()
 	panic(msg)
 }
 
 // Panicln print a log entry to the destine and call panic.
 func (l *Slog) Panicln(v ...interface{}) {
+	l.helper()
 	l = l.copy()
 	l.Log.di(fnLevelDi)
 	msg := fmt.Sprint(v...)
 	l.Log.Message(msg)
 	l.Log.Priority = PanicPrio
+	end := l.ending()
 	l.commit()
-	l.Writter.Close()
+	end.shutdown()
 	panic(msg)
 }
 
 // GoPanic is use when recover from a panic and the panic must be logged
 func (l *Slog) GoPanic(r interface{}, stack []byte, cont bool) {
+	l.helper()
 	var msg string
 	l = l.copy()
 	l.Log.di(fnLevelDi)
@@ -781,14 +1010,16 @@ func (l *Slog) GoPanic(r interface{}, stack []byte, cont bool) {
 	}
 	l.Log.Message(msg + "\n{" + string(stack) + "}")
 	l.Log.Priority = PanicPrio
+	end := l.ending()
 	l.commit()
 	if !cont {
-		l.Writter.Close()
-		l.Exiter(1)
+		end.shutdown()
+		end.exit(1)
 	}
 }
 
 func (l *Slog) Write(p []byte) (n int, err error) {
+	l.helper()
 	l.wlck.Lock()
 	defer l.wlck.Unlock()
 	l.wbuf = append(l.wbuf, p...)
@@ -826,6 +1057,7 @@ func init() {
 		println("SLOG: Fail to start log:", err)
 		os.Exit(1)
 	}
+	loadLevelEnv()
 }
 
 // DefaultLogger return the default logger. Mainly to be used with Writer interface.
@@ -833,8 +1065,12 @@ func DefaultLogger() *Slog {
 	return log
 }
 
-// SetOutput sets the commit out put to w.
+// SetOutput sets the commit out put to w. If a level spec with level was
+// applied, like the one in LevelEnv, its level is used instead of level.
 func SetOutput(domain string, level Level, w io.WriteCloser, commiter func(sl *Slog), formatter func(l *Slog) ([]byte, error), nl int) error {
+	if l := defaultLevel(); l != 0 {
+		level = l
+	}
 	log = &Slog{
 		Commit:    commiter,
 		Formatter: formatter,
@@ -848,23 +1084,17 @@ func SetOutput(domain string, level Level, w io.WriteCloser, commiter func(sl *S
 	return nil
 }
 
-// Exiter configures a function that will be called to exit the app.
+// Exiter configures a function that will be called to exit the app. It's safe
+// to call Exiter while other goroutines are logging.
 func Exiter(fn func(int)) error {
-	log.Exiter = fn
-	err := log.Init(string(log.Log.Domain), numLogs)
-	if err != nil {
-		return e.Forward(err)
-	}
+	log.ChangeExiter(fn)
 	return nil
 }
 
-// SetLevel set the level to filter log entries.
+// SetLevel set the level to filter log entries. It's safe to call SetLevel
+// while other goroutines are logging.
 func SetLevel(level Level) error {
-	log.Level = level
-	err := log.Init(string(log.Log.Domain), numLogs)
-	if err != nil {
-		return e.Forward(err)
-	}
+	log.ChangeLevel(level)
 	return nil
 }
 
@@ -898,37 +1128,113 @@ func Tag(tags ...string) *Slog {
 	return log.Tag(tags...).di(fnLevelDi)
 }
 
+// With attach fields to the log entry.
+func With(keyvals ...interface{}) *Slog {
+	return log.With(keyvals...).di(fnLevelDi)
+}
+
+// Fields attach typed fields to the log entry.
+func Fields(fields ...Field) *Slog {
+	return log.Fields(fields...).di(fnLevelDi)
+}
+
 // Print prints a log entry to the destine, this is determined by the commit
 // function.
 func Print(vals ...interface{}) {
+	if !log.Enabled(log.Log.Priority) {
+		return
+	}
 	log.di(fnLevelDiPlus1).Print(vals...)
 }
 
 // Printf prints a formated log entry to the destine.
 func Printf(str string, vals ...interface{}) {
+	if !log.Enabled(log.Log.Priority) {
+		return
+	}
 	log.di(fnLevelDiPlus1).Printf(str, vals...)
 }
 
 // Println prints a log entry to the destine.
 func Println(vals ...interface{}) {
+	if !log.Enabled(log.Log.Priority) {
+		return
+	}
 	log.di(fnLevelDiPlus1).Println(vals...)
 }
 
+// Warn logs a warning.
+func Warn(vals ...interface{}) {
+	if !log.Enabled(WarnPrio) {
+		return
+	}
+	log.di(fnLevelDiPlus1).Warn(vals...)
+}
+
+// Warnf logs a warning formated.
+func Warnf(str string, vals ...interface{}) {
+	if !log.Enabled(WarnPrio) {
+		return
+	}
+	log.di(fnLevelDiPlus1).Warnf(str, vals...)
+}
+
+// Warnln logs a warning.
+func Warnln(vals ...interface{}) {
+	if !log.Enabled(WarnPrio) {
+		return
+	}
+	log.di(fnLevelDiPlus1).Warnln(vals...)
+}
+
 // Error logs an error.
 func Error(vals ...interface{}) {
+	if !log.Enabled(ErrorPrio) {
+		return
+	}
 	log.di(fnLevelDiPlus1).Error(vals...)
 }
 
 // Errorf logs an error formated.
 func Errorf(str string, vals ...interface{}) {
+	if !log.Enabled(ErrorPrio) {
+		return
+	}
 	log.di(fnLevelDiPlus1).Errorf(str, vals...)
 }
 
 // Errorln logs an error.
 func Errorln(vals ...interface{}) {
+	if !log.Enabled(ErrorPrio) {
+		return
+	}
 	log.di(fnLevelDiPlus1).Errorln(vals...)
 }
 
+// Enabled returns true if an entry with priority level pass the level of the
+// default logger.
+func Enabled(level Level) bool {
+	return log.Enabled(level)
+}
+
+// Lazy prints the message returned by fn with priority level. fn is called
+// only if the entry pass the level.
+func Lazy(level Level, fn func() string) {
+	if !log.Enabled(level) {
+		return
+	}
+	log.di(fnLevelDiPlus1).Lazy(level, fn)
+}
+
+// Lazyf prints a formated log entry with priority level. The message is
+// formated only if the entry pass the level.
+func Lazyf(level Level, str string, vals ...interface{}) {
+	if !log.Enabled(level) {
+		return
+	}
+	log.di(fnLevelDiPlus1).Lazyf(level, str, vals...)
+}
+
 // Fatal print a log entry to the destine and exit with 1.
 func Fatal(vals ...interface{}) {
 	log.di(fnLevelDiPlus1).Fatal(vals...)
@@ -974,6 +1280,11 @@ func InfoLevel() *Slog {
 	return log.InfoLevel().di(fnLevelDi)
 }
 
+// WarnLevel set the log level to warning
+func WarnLevel() *Slog {
+	return log.WarnLevel().di(fnLevelDi)
+}
+
 // ErrorLevel set the log level to error
 func ErrorLevel() *Slog {
 	return log.ErrorLevel().di(fnLevelDi)
@@ -987,7 +1298,7 @@ func GoPanic(r interface{}, stack []byte, cont bool) {
 // RecoverBufferStack amount of buffer to store the stack.
 var RecoverBufferStack = 4096
 
-// Recover from panic and log the stack. If notexit is false, call l.Exiter(1),
+// Recover from panic and log the stack. If notexit is false, call the exiter,
 // if not continue.
 func Recover(notexit bool) {
 	if r := recover(); r != nil {
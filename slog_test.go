@@ -17,6 +17,7 @@ import (
 
 	"github.com/fcavani/e"
 	. "github.com/fcavani/slog"
+	"github.com/fcavani/slog/systemd"
 	"github.com/sirupsen/logrus"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -35,6 +36,7 @@ var levels []level = []level{
 	{ProtoPrio, []byte("protocol"), "protocol"},
 	{DebugPrio, []byte("debug"), "debug"},
 	{InfoPrio, []byte("info"), "info"},
+	{WarnPrio, []byte("warning"), "warning"},
 	{ErrorPrio, []byte("error"), "error"},
 	{FatalPrio, []byte("fatal"), "fatal"},
 	{PanicPrio, []byte("panic"), "panic"},
@@ -185,13 +187,13 @@ func TestPrint(t *testing.T) {
 	logger = logger.Di().MakeDefault()
 
 	logger.Tag("tag1", "tag2").Println(msg)
-	AssertLine(t, buf, "teste - info - tag1 tag2 - slog/slog_test.go:187 - benchmark log test")
+	AssertLine(t, buf, "teste - info - tag1 tag2 - slog/slog_test.go:189 - benchmark log test")
 
 	logger.Tag("tag1", "tag2").ErrorLevel().Di().Println(msg)
-	AssertLine(t, buf, "teste - error - tag1 tag2 - slog/slog_test.go:190 - benchmark log test")
+	AssertLine(t, buf, "teste - error - tag1 tag2 - slog/slog_test.go:192 - benchmark log test")
 
 	logger.Tag("tag1", "tag2").ErrorLevel().Println(msg)
-	AssertLine(t, buf, "teste - error - tag1 tag2 - slog/slog_test.go:193 - benchmark log test")
+	AssertLine(t, buf, "teste - error - tag1 tag2 - slog/slog_test.go:195 - benchmark log test")
 
 	logger.Tag("tag1", "tag2").ErrorLevel().NoDi().Println(msg)
 	AssertLine(t, buf, "teste - error - tag1 tag2 - benchmark log test")
@@ -199,7 +201,7 @@ func TestPrint(t *testing.T) {
 	logger = logger.DebugLevel().MakeDefault()
 
 	logger.Tag("tag1", "tag2").Println(msg)
-	AssertLine(t, buf, "teste - debug - tag1 tag2 - slog/slog_test.go:201 - benchmark log test")
+	AssertLine(t, buf, "teste - debug - tag1 tag2 - slog/slog_test.go:203 - benchmark log test")
 
 }
 
@@ -222,7 +224,7 @@ func TestPrintJSON(t *testing.T) {
 		Timestamp: "",
 		Tags:      []string{"tag1", "tag2"},
 		Message:   "benchmark log test",
-		File:      "slog/slog_test.go:218",
+		File:      "slog/slog_test.go:220",
 	})
 }
 
@@ -291,12 +293,12 @@ func TestFreeFunc(t *testing.T) {
 	AssertLine(t, buf, "teste - error - benchmark log test")
 
 	Di().Print(msg)
-	AssertLine(t, buf, "teste - info - slog/slog_test.go:293 - benchmark log test")
+	AssertLine(t, buf, "teste - info - slog/slog_test.go:295 - benchmark log test")
 	DebugInfo()
 	NoDi().Print(msg)
 	AssertLine(t, buf, "teste - info - benchmark log test")
 	Print(msg)
-	AssertLine(t, buf, "teste - info - slog/slog_test.go:298 - benchmark log test")
+	AssertLine(t, buf, "teste - info - slog/slog_test.go:300 - benchmark log test")
 }
 
 func TestFreeFuncPanic(t *testing.T) {
@@ -392,7 +394,7 @@ func TestFreeFuncGoPanic(t *testing.T) {
 	AssertLine(t, buf, "teste - panic - 42")
 	DebugInfo()
 	GoPanic("panic test", []byte{}, true)
-	AssertLine(t, buf, "teste - panic - slog/slog_test.go:394 - panic test")
+	AssertLine(t, buf, "teste - panic - slog/slog_test.go:396 - panic test")
 }
 
 func TestCloseWriter(t *testing.T) {
@@ -579,24 +581,24 @@ func TestSetLevel(t *testing.T) {
 	logger = logger.Di().MakeDefault()
 
 	logger.Tag("tag1", "tag2").Println(msg)
-	AssertLine(t, buf, "teste - info - tag1 tag2 - slog/slog_test.go:581 - benchmark log test")
+	AssertLine(t, buf, "teste - info - tag1 tag2 - slog/slog_test.go:583 - benchmark log test")
 
 	logger.Tag("tag1", "tag2").ProtoLevel().Println(msg)
 	AssertEOF(t, buf)
 
 	logger.Tag("tag1", "tag2").SetLevel(ProtoPrio).ProtoLevel().Println(msg)
-	AssertLine(t, buf, "teste - protocol - tag1 tag2 - slog/slog_test.go:587 - benchmark log test")
+	AssertLine(t, buf, "teste - protocol - tag1 tag2 - slog/slog_test.go:589 - benchmark log test")
 
 	logger.Tag("tag1", "tag2").ProtoLevel().Println(msg)
 	AssertEOF(t, buf)
 
 	logger.Tag("tag1", "tag2").Println(msg)
-	AssertLine(t, buf, "teste - info - tag1 tag2 - slog/slog_test.go:593 - benchmark log test")
+	AssertLine(t, buf, "teste - info - tag1 tag2 - slog/slog_test.go:595 - benchmark log test")
 
 	logger = logger.SetLevel(ProtoPrio).MakeDefault()
 
 	logger.Tag("tag1", "tag2").ProtoLevel().Println(msg)
-	AssertLine(t, buf, "teste - protocol - tag1 tag2 - slog/slog_test.go:598 - benchmark log test")
+	AssertLine(t, buf, "teste - protocol - tag1 tag2 - slog/slog_test.go:600 - benchmark log test")
 }
 
 func TestFreeSetLevel(t *testing.T) {
@@ -671,31 +673,31 @@ func BenchmarkZapLoggerPrintf(b *testing.B) {
 	}
 }
 
-// A Syncer is a spy for the Sync portion of zapcore.WriteSyncer.
-type Syncer struct {
+// A spySyncer is a spy for the Sync portion of zapcore.WriteSyncer.
+type spySyncer struct {
 	err    error
 	called bool
 }
 
 // SetError sets the error that the Sync method will return.
-func (s *Syncer) SetError(err error) {
+func (s *spySyncer) SetError(err error) {
 	s.err = err
 }
 
 // Sync records that it was called, then returns the user-supplied error (if
 // any).
-func (s *Syncer) Sync() error {
+func (s *spySyncer) Sync() error {
 	s.called = true
 	return s.err
 }
 
 // Called reports whether the Sync method was called.
-func (s *Syncer) Called() bool {
+func (s *spySyncer) Called() bool {
 	return s.called
 }
 
 // A Discarder sends all writes to ioutil.Discard.
-type Discarder struct{ Syncer }
+type Discarder struct{ spySyncer }
 
 // Write implements io.Writer.
 func (d *Discarder) Write(b []byte) (int, error) {
@@ -763,5 +765,58 @@ func TestFreeDI(t *testing.T) {
 	DebugInfo()
 
 	ProtoLevel().Tag("teste").Println(msg)
-	AssertLine(t, buf, "teste - protocol - teste - slog/slog_test.go:765 - benchmark log test")
+	AssertLine(t, buf, "teste - protocol - teste - slog/slog_test.go:767 - benchmark log test")
+}
+
+func TestWarn(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter: buf,
+		Level:   WarnPrio,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	logger.InfoLevel().Println(msg)
+	AssertEOF(t, buf)
+
+	logger.Warn(msg)
+	AssertLine(t, buf, "teste - warning - benchmark log test")
+	logger.Warnf("%v", msg)
+	AssertLine(t, buf, "teste - warning - benchmark log test")
+	logger.Warnln(msg)
+	AssertLine(t, buf, "teste - warning - benchmark log test")
+	logger.Tag("tag1").WarnLevel().Print(msg)
+	AssertLine(t, buf, "teste - warning - tag1 - benchmark log test")
+
+	level, err := ParseLevel("warn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level != WarnPrio {
+		t.Fatal("wrong level")
+	}
+	if Prior2Sd(WarnPrio) != systemd.PriWarning {
+		t.Fatal("wrong systemd priority")
+	}
+}
+
+func TestFreeFuncWarn(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+	err := SetOutput("teste", ProtoPrio, buf, nil, nil, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Warn(msg)
+	AssertLine(t, buf, "teste - warning - benchmark log test")
+	Warnf("%v", msg)
+	AssertLine(t, buf, "teste - warning - benchmark log test")
+	Warnln(msg)
+	AssertLine(t, buf, "teste - warning - benchmark log test")
+	WarnLevel().Print(msg)
+	AssertLine(t, buf, "teste - warning - benchmark log test")
 }
@@ -0,0 +1,63 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+// Package slogtest has helpers to test code that logs with slog.
+package slogtest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a slog.Clock that only moves when told to. It's safe for
+// concurrent use.
+//
+//	clock := slogtest.NewFakeClock(time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC))
+//	logger := &slog.Slog{Clock: clock}
+type FakeClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+// NewFakeClock returns a FakeClock stopped at t.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+// NewSteppingClock returns a FakeClock that starts at t and advances step
+// after each call to Now.
+func NewSteppingClock(t time.Time, step time.Duration) *FakeClock {
+	return &FakeClock{now: t, step: step}
+}
+
+// Now returns the time of the clock and advances it by the step.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+// Add advances the clock by d.
+func (c *FakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set sets the time of the clock.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// SetStep sets how much the clock advances after each call to Now.
+func (c *FakeClock) SetStep(step time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.step = step
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slogtest_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/fcavani/e"
+	"github.com/fcavani/slog"
+	. "github.com/fcavani/slog/slogtest"
+)
+
+type writerCloser struct {
+	*bytes.Buffer
+}
+
+func (wc *writerCloser) Close() error {
+	return nil
+}
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := NewFakeClock(start)
+	if !clock.Now().Equal(start) || !clock.Now().Equal(start) {
+		t.Fatal("clock moved")
+	}
+	clock.Add(time.Minute)
+	if !clock.Now().Equal(start.Add(time.Minute)) {
+		t.Fatal("clock didn't advance")
+	}
+	clock.Set(start)
+	clock.SetStep(time.Second)
+	clock.Now()
+	if !clock.Now().Equal(start.Add(time.Second)) {
+		t.Fatal("clock didn't step")
+	}
+
+	clock = NewSteppingClock(start, time.Millisecond)
+	clock.Now()
+	if !clock.Now().Equal(start.Add(time.Millisecond)) {
+		t.Fatal("clock didn't step")
+	}
+}
+
+func TestFakeClockLogger(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	clock := NewSteppingClock(time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC), time.Second)
+	logger := &slog.Slog{
+		Writter: buf,
+		Clock:   clock,
+		Time:    slog.TimeFormat{UTC: true},
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+
+	logger.Print("first")
+	logger.Tag("tag1").Error("second")
+	logger.Tag("tag1").MakeDefault().Print("third")
+	expected := "teste - 2018/01/02 03:04:05 - info - first\n" +
+		"teste - 2018/01/02 03:04:06 - error - tag1 - second\n" +
+		"teste - 2018/01/02 03:04:07 - info - tag1 - third\n"
+	if buf.String() != expected {
+		t.Fatalf("wrong output:\n%v", buf.String())
+	}
+	buf.Reset()
+
+	logger = &slog.Slog{
+		Writter:   buf,
+		Formatter: slog.Logfmt,
+		Clock:     clock,
+	}
+	err = logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	clock.Set(time.Date(2018, 1, 2, 3, 4, 5, 6000000, time.UTC))
+	logger.Print("logfmt")
+	if s := buf.String(); s != "time=2018-01-02T03:04:05.006000000Z level=info domain=teste msg=logfmt\n" {
+		t.Fatalf("wrong output: %q", s)
+	}
+}
@@ -0,0 +1,415 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fcavani/e"
+)
+
+// SyslogFormat is the format of the syslog messages.
+type SyslogFormat uint8
+
+const (
+	// RFC5424 is the syslog protocol format, with structured data.
+	RFC5424 SyslogFormat = iota
+	// RFC3164 is the BSD syslog format.
+	RFC3164
+)
+
+// Facility is the syslog facility.
+type Facility uint8
+
+// Syslog facilities.
+const (
+	FacKern Facility = iota
+	FacUser
+	FacMail
+	FacDaemon
+	FacAuth
+	FacSyslog
+	FacLpr
+	FacNews
+	FacUucp
+	FacCron
+	FacAuthPriv
+	FacFtp
+	_
+	_
+	_
+	_
+	FacLocal0
+	FacLocal1
+	FacLocal2
+	FacLocal3
+	FacLocal4
+	FacLocal5
+	FacLocal6
+	FacLocal7
+)
+
+// DefaultSDID is the structured data id used if Syslog.SDID is empty. 32473
+// is the enterprise number reserved for documentation.
+const DefaultSDID = "slog@32473"
+
+var localSyslog = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// Syslog is a committer that sends the entries to a syslog server. The level is
+// mapped to the severity like Prior2Sd does, tags, fields and the debug
+// information are sent in the STRUCTURED-DATA of RFC 5424 messages. With the
+// tcp transport the messages are framed with octet counting (RFC 6587) and with
+// the unix stream transport they are terminated by a new line. If the
+// connection fails, Syslog reconnects in the next entry and if that fails too
+// the entry is written in the Slog Writter with the FallbackFormater.
+//
+//	sl := &slog.Syslog{Network: "tcp", Addr: "logs.example.com:514"}
+//	logger := &slog.Slog{Commit: sl.Commit}
+type Syslog struct {
+	// Network is the network of the syslog server: "udp", "tcp", "unixgram"
+	// or "unix". If empty, the local syslog socket is used.
+	Network string
+	// Addr is the address of the syslog server.
+	Addr string
+	// Format is the message format.
+	Format SyslogFormat
+	// Facility is the syslog facility. User processes can't use FacKern, so
+	// the zero value means FacUser.
+	Facility Facility
+	// Hostname is the host name in the message. The default is the name of
+	// the host.
+	Hostname string
+	// AppName is the application name. The default is the Slog domain.
+	AppName string
+	// SDID is the id of the structured data element.
+	SDID string
+	// Timeout is the timeout to connect and write.
+	Timeout time.Duration
+
+	mu      sync.Mutex
+	conn    net.Conn
+	framing framing
+}
+
+type framing uint8
+
+const (
+	noFraming framing = iota
+	octetCounting
+	newLine
+)
+
+func (s *Syslog) dial(network, addr string) (net.Conn, error) {
+	if s.Timeout > 0 {
+		return net.DialTimeout(network, addr, s.Timeout)
+	}
+	return net.Dial(network, addr)
+}
+
+func (s *Syslog) connect() error {
+	if s.Network != "" {
+		conn, err := s.dial(s.Network, s.Addr)
+		if err != nil {
+			return e.Forward(err)
+		}
+		s.conn = conn
+		switch s.Network {
+		case "tcp", "tcp4", "tcp6":
+			s.framing = octetCounting
+		case "unix":
+			s.framing = newLine
+		default:
+			s.framing = noFraming
+		}
+		return nil
+	}
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range localSyslog {
+			conn, err := s.dial(network, path)
+			if err == nil {
+				s.conn = conn
+				s.framing = noFraming
+				if network == "unix" {
+					s.framing = newLine
+				}
+				return nil
+			}
+		}
+	}
+	return e.New("syslog not available")
+}
+
+func (s *Syslog) send(msg []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	for i := 0; i < 2; i++ {
+		if s.conn == nil {
+			err = s.connect()
+			if err != nil {
+				return err
+			}
+		}
+		if s.Timeout > 0 {
+			s.conn.SetWriteDeadline(time.Now().Add(s.Timeout))
+		}
+		switch s.framing {
+		case octetCounting:
+			frame := Pool.Get().([]byte)
+			frame = strconv.AppendInt(frame, int64(len(msg)), 10)
+			frame = append(frame, ' ')
+			frame = append(frame, msg...)
+			_, err = s.conn.Write(frame)
+			Pool.Put(frame[:0])
+		case newLine:
+			_, err = s.conn.Write(append(msg, '\n'))
+		default:
+			_, err = s.conn.Write(msg)
+		}
+		if err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	return e.Forward(err)
+}
+
+// Commit sends the entry to the syslog server.
+func (s *Syslog) Commit(sl *Slog) {
+	sl.stamp()
+	if sl.Log.DoDi && sl.Log.file == "" {
+		sl.Log.file = debugInfo(sl.Log.DiLevel)
+	}
+
+	buf := Pool.Get().([]byte)
+	switch s.Format {
+	case RFC3164:
+		s.formatRFC3164(&buf, sl)
+	default:
+		s.formatRFC5424(&buf, sl)
+	}
+	err := s.send(buf)
+	Pool.Put(buf[:0])
+	if err == nil {
+		return
+	}
+	println("SLOG syslog failed:", err.Error())
+
+	buf, err = FallbackFormater(sl)
+	if err != nil {
+		println("SLOG writer failed:", err)
+		return
+	}
+	sl.Lck.Lock()
+	_, err = sl.Writter.Write(buf)
+	if err != nil {
+		println("SLOG writer failed:", err)
+	}
+	Pool.Put(buf[:0])
+	sl.Lck.Unlock()
+}
+
+// Close closes the connection with the syslog server.
+func (s *Syslog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	if err != nil {
+		return e.Forward(err)
+	}
+	return nil
+}
+
+func (s *Syslog) appendPri(buf *[]byte, level Level) {
+	*buf = append(*buf, '<')
+	fac := s.Facility
+	if fac == FacKern {
+		fac = FacUser
+	}
+	*buf = strconv.AppendInt(*buf, int64(fac)*8+int64(Prior2Sd(level)), 10)
+	*buf = append(*buf, '>')
+}
+
+func (s *Syslog) hostname() string {
+	if s.Hostname != "" {
+		return s.Hostname
+	}
+	if Hostname != "" {
+		return Hostname
+	}
+	return "-"
+}
+
+func (s *Syslog) appName(sl *Slog) string {
+	if s.AppName != "" {
+		return s.AppName
+	}
+	return string(sl.Log.Domain)
+}
+
+func syslogMessage(sl *Slog) string {
+	m := sl.Log.msg
+	if len(m) > 0 && m[len(m)-1] == '\n' {
+		m = m[:len(m)-1]
+	}
+	return m
+}
+
+// appendHeaderField appends a RFC 5424 header field: printable US-ASCII
+// without spaces, at most max characters, "-" if empty.
+func appendHeaderField(buf *[]byte, s string, max int) {
+	if len(s) == 0 {
+		*buf = append(*buf, '-')
+		return
+	}
+	for i := 0; i < len(s) && i < max; i++ {
+		c := s[i]
+		if c < 33 || c > 126 {
+			c = '_'
+		}
+		*buf = append(*buf, c)
+	}
+}
+
+// appendSDName appends a SD-NAME: printable US-ASCII except '=', ' ', ']' and
+// '"', at most 32 characters.
+func appendSDName(buf *[]byte, s string) {
+	if len(s) == 0 {
+		*buf = append(*buf, '_')
+		return
+	}
+	for i := 0; i < len(s) && i < 32; i++ {
+		c := s[i]
+		if c < 33 || c > 126 || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		*buf = append(*buf, c)
+	}
+}
+
+func appendSDEscaped(buf *[]byte, s string) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '"' || c == '\\' || c == ']' {
+			*buf = append(*buf, '\\')
+		}
+		*buf = append(*buf, c)
+	}
+}
+
+func appendSDValue(buf *[]byte, v interface{}) {
+	switch val := v.(type) {
+	case string:
+		appendSDEscaped(buf, val)
+	case []byte:
+		appendSDEscaped(buf, string(val))
+	case error:
+		appendSDEscaped(buf, val.Error())
+	case fmt.Stringer:
+		appendSDEscaped(buf, val.String())
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		appendTextValue(buf, val)
+	default:
+		appendSDEscaped(buf, fmt.Sprint(val))
+	}
+}
+
+func appendSDParam(buf *[]byte, name string, value interface{}) {
+	*buf = append(*buf, ' ')
+	appendSDName(buf, name)
+	*buf = append(*buf, '=', '"')
+	appendSDValue(buf, value)
+	*buf = append(*buf, '"')
+}
+
+func (s *Syslog) formatRFC5424(buf *[]byte, sl *Slog) {
+	s.appendPri(buf, sl.Log.Priority)
+	*buf = append(*buf, '1', ' ')
+	t := sl.Log.Timestamp
+	if sl.Time.UTC {
+		t = t.UTC()
+	}
+	appendRFC3339(buf, t, 6)
+	*buf = append(*buf, ' ')
+	appendHeaderField(buf, s.hostname(), 255)
+	*buf = append(*buf, ' ')
+	appendHeaderField(buf, s.appName(sl), 48)
+	*buf = append(*buf, ' ')
+	appendHeaderField(buf, PID, 128)
+	*buf = append(*buf, ' ', '-', ' ')
+
+	tags := *sl.Log.Tags
+	fields := *sl.Log.Fields
+	if len(tags) == 0 && len(fields) == 0 && !sl.Log.DoDi {
+		*buf = append(*buf, '-')
+	} else {
+		*buf = append(*buf, '[')
+		sdid := s.SDID
+		if sdid == "" {
+			sdid = DefaultSDID
+		}
+		appendSDName(buf, sdid)
+		if len(tags) > 0 {
+			*buf = append(*buf, " tags=\""...)
+			for i := 0; i < len(tags); i++ {
+				if i > 0 {
+					*buf = append(*buf, ' ')
+				}
+				appendSDEscaped(buf, tags[i])
+			}
+			*buf = append(*buf, '"')
+		}
+		if sl.Log.DoDi {
+			appendSDParam(buf, "file", sl.Log.file)
+		}
+		for _, f := range fields {
+			appendSDParam(buf, f.Key, f.Value)
+		}
+		*buf = append(*buf, ']')
+	}
+
+	if m := syslogMessage(sl); len(m) > 0 {
+		*buf = append(*buf, ' ')
+		*buf = append(*buf, m...)
+	}
+}
+
+func (s *Syslog) formatRFC3164(buf *[]byte, sl *Slog) {
+	s.appendPri(buf, sl.Log.Priority)
+	t := sl.Log.Timestamp
+	if sl.Time.UTC {
+		t = t.UTC()
+	}
+	*buf = t.AppendFormat(*buf, time.Stamp)
+	*buf = append(*buf, ' ')
+	appendHeaderField(buf, s.hostname(), 255)
+	*buf = append(*buf, ' ')
+	appendHeaderField(buf, s.appName(sl), 32)
+	*buf = append(*buf, '[')
+	*buf = append(*buf, PID...)
+	*buf = append(*buf, ']', ':', ' ')
+	if len(*sl.Log.Tags) > 0 {
+		*buf = append(*buf, sl.Log.Tags.String()...)
+		*buf = append(*buf, sepTags...)
+	}
+	if len(*sl.Log.Fields) > 0 {
+		sl.Log.Fields.encodeText(buf)
+		*buf = append(*buf, sepTags...)
+	}
+	if sl.Log.DoDi {
+		*buf = append(*buf, sl.Log.file...)
+		*buf = append(*buf, sep...)
+	}
+	*buf = append(*buf, syslogMessage(sl)...)
+}
@@ -0,0 +1,209 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func newSyslogLogger(t *testing.T, sl *Syslog) *Slog {
+	logger := &Slog{
+		Writter: &writerCloser{bytes.NewBuffer([]byte{})},
+		Level:   DebugPrio,
+		Commit:  sl.Commit,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	return logger
+}
+
+func assertSyslog(t *testing.T, msg, pattern string) {
+	re := regexp.MustCompile("^" + pattern + "$")
+	if !re.MatchString(msg) {
+		t.Fatalf("syslog message is wrong: %q !~ %q", msg, pattern)
+	}
+}
+
+const rfc5424Time = `\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}(Z|[+-]\d\d:\d\d)`
+
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	defer conn.Close()
+
+	sl := &Syslog{
+		Network:  "udp",
+		Addr:     conn.LocalAddr().String(),
+		Hostname: "host",
+		Facility: FacLocal0,
+	}
+	defer sl.Close()
+	logger := newSyslogLogger(t, sl)
+
+	read := func() string {
+		buf := make([]byte, 2048)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(e.Trace(e.Forward(err)))
+		}
+		return string(buf[:n])
+	}
+
+	pid := strconv.Itoa(os.Getpid())
+
+	logger.Println(msg)
+	assertSyslog(t, read(), `<134>1 `+rfc5424Time+` host teste `+pid+` - - benchmark log test`)
+
+	logger.Tag("tag1", "tag2").With("user", "jo\"]hn", "id", 42).Error(msg)
+	assertSyslog(t, read(), `<131>1 `+rfc5424Time+` host teste `+pid+` - \[slog@32473 tags="tag1 tag2" user="jo\\"\\]hn" id="42"\] benchmark log test`)
+
+	logger.WarnLevel().Print(msg)
+	assertSyslog(t, read(), `<132>1 .*`)
+
+	sl.Format = RFC3164
+	logger.DebugLevel().Tag("tag1").Print(msg)
+	assertSyslog(t, read(), `<135>\w{3} [ \d]\d \d\d:\d\d:\d\d host teste\[`+pid+`\]: tag1 - benchmark log test`)
+}
+
+func TestSyslogTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	defer ln.Close()
+
+	msgs := make(chan string, 10)
+	go func() {
+		for i := 0; ; i++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			r := bufio.NewReader(conn)
+			for {
+				l, err := r.ReadString(' ')
+				if err != nil {
+					break
+				}
+				n, err := strconv.Atoi(l[:len(l)-1])
+				if err != nil {
+					t.Error(err)
+					break
+				}
+				buf := make([]byte, n)
+				_, err = io.ReadFull(r, buf)
+				if err != nil {
+					break
+				}
+				msgs <- string(buf)
+				// Drop the first connection to force a reconnection.
+				if i == 0 {
+					break
+				}
+			}
+			conn.Close()
+		}
+	}()
+
+	sl := &Syslog{
+		Network:  "tcp",
+		Addr:     ln.Addr().String(),
+		Hostname: "host",
+		Timeout:  time.Second,
+	}
+	defer sl.Close()
+	logger := newSyslogLogger(t, sl)
+
+	logger.Print("first")
+	select {
+	case m := <-msgs:
+		assertSyslog(t, m, `<14>1 `+rfc5424Time+` host teste \d+ - - first`)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	// The writes after the server closed the connection fail, eventually, and
+	// the committer reconnects.
+	deadline := time.After(5 * time.Second)
+	for {
+		logger.Print("second")
+		select {
+		case m := <-msgs:
+			assertSyslog(t, m, `<14>1 `+rfc5424Time+` host teste \d+ - - second`)
+			return
+		case <-deadline:
+			t.Fatal("timeout")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestSyslogUnixgram(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log")
+
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	defer conn.Close()
+
+	sl := &Syslog{
+		Network:  "unixgram",
+		Addr:     path,
+		Hostname: "host",
+	}
+	defer sl.Close()
+	logger := newSyslogLogger(t, sl)
+
+	logger.Di().Print(msg)
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	assertSyslog(t, string(buf[:n]), `<14>1 `+rfc5424Time+` host teste \d+ - \[slog@32473 file="slog/syslog_test.go:\d+"\] benchmark log test`)
+}
+
+func TestSyslogFallback(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+	sl := &Syslog{
+		Network: "unixgram",
+		Addr:    filepath.Join(dir, "nothing"),
+	}
+	logger := &Slog{
+		Writter: buf,
+		Level:   DebugPrio,
+		Commit:  sl.Commit,
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	logger.Print(msg)
+	AssertLine(t, buf, "teste - info - benchmark log test")
+}
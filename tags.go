@@ -21,6 +21,20 @@ func (t *tags) copy() *tags {
 	return &tdst
 }
 
+// copyTo copies t to dst reusing its memory.
+func (t *tags) copyTo(dst *tags) {
+	*dst = append((*dst)[:0], *t...)
+}
+
+// reset empties t keeping its memory.
+func (t *tags) reset() {
+	a := *t
+	for i := range a {
+		a[i] = ""
+	}
+	*t = a[:0]
+}
+
 func (t tags) String() (str string) {
 	if t == nil {
 		return ""
@@ -62,18 +76,16 @@ func (t *tags) Clean() {
 
 func (t tags) EncodeJSON(buf *[]byte) {
 	if t == nil {
-		*buf = append(*buf, []byte("[]")...)
+		*buf = append(*buf, "[]"...)
 		return
 	}
-	*buf = append(*buf, []byte("[")...)
+	*buf = append(*buf, '[')
 	l := len(t) - 1
 	for i := 0; i < len(t); i++ {
-		*buf = append(*buf, []byte("\"")...)
-		*buf = append(*buf, []byte(t[i])...)
-		*buf = append(*buf, []byte("\"")...)
+		appendJSONString(buf, t[i])
 		if i < l {
-			*buf = append(*buf, []byte(",")...)
+			*buf = append(*buf, ',')
 		}
 	}
-	*buf = append(*buf, []byte("]")...)
+	*buf = append(*buf, ']')
 }
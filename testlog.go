@@ -0,0 +1,82 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"sync"
+)
+
+func noHelper() {}
+
+// TBWriter is an io.WriteCloser that sends each entry to the Logf method of
+// a test, so the output of a test is shown with the test that produced it. If
+// the test supports Cleanup, like testing.TB of go 1.14 and newer, the writes
+// after the end of the test are discarded, avoiding the panic of Log in
+// goroutine after the test has completed. Otherwise call Close before the test
+// returns.
+type TBWriter struct {
+	tb      TB
+	mu      sync.Mutex
+	stopped bool
+}
+
+// NewTBWriter returns a TBWriter that logs in tb.
+func NewTBWriter(tb TB) *TBWriter {
+	w := &TBWriter{tb: tb}
+	if c, ok := tb.(cleaner); ok {
+		c.Cleanup(w.stop)
+	}
+	return w
+}
+
+func (w *TBWriter) stop() {
+	w.mu.Lock()
+	w.stopped = true
+	w.mu.Unlock()
+}
+
+// Write logs p in the test, without the trailing new line.
+func (w *TBWriter) Write(p []byte) (n int, err error) {
+	w.tb.Helper()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return len(p), nil
+	}
+	s := p
+	if len(s) > 0 && s[len(s)-1] == '\n' {
+		s = s[:len(s)-1]
+	}
+	w.tb.Logf("%s", s)
+	return len(p), nil
+}
+
+// Close stops the writer.
+func (w *TBWriter) Close() error {
+	w.stop()
+	return nil
+}
+
+// NewTestLogger returns a logger that logs all levels in the test with a
+// TBWriter. The location shown by the test is the one of the caller of the
+// logger methods, not the one inside slog.
+//
+//	func TestSomething(t *testing.T) {
+//		logger := slog.NewTestLogger(t, "test")
+//		logger.Print("starting") // shown as something_test.go:12: test - ...
+//	}
+func NewTestLogger(tb TB, domain string) *Slog {
+	tb.Helper()
+	l := &Slog{
+		Level:   ProtoPrio,
+		Writter: NewTBWriter(tb),
+		helper:  tb.Helper,
+	}
+	err := l.Init(domain, 1)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return l
+}
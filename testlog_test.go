@@ -0,0 +1,105 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	. "github.com/fcavani/slog"
+)
+
+// lineTB records the file and line that the testing package would report,
+// skipping the frames of the functions that called Helper.
+type lineTB struct {
+	fakeTB
+	helpers map[string]bool
+	lines   []string
+}
+
+func (l *lineTB) Helper() {
+	pc, _, _, _ := runtime.Caller(1)
+	l.helpers[runtime.FuncForPC(pc).Name()] = true
+}
+
+func (l *lineTB) Logf(format string, args ...interface{}) {
+	l.fakeTB.Logf(format, args...)
+	pcs := make([]uintptr, 50)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !l.helpers[f.Function] {
+			s := strings.Split(f.File, "/")
+			l.lines = append(l.lines, s[len(s)-1]+":"+itoa(f.Line))
+			return
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+func itoa(i int) string {
+	buf := make([]byte, 0, 8)
+	Itoa(&buf, i, 1)
+	return string(buf)
+}
+
+func TestTestLogger(t *testing.T) {
+	tb := &lineTB{helpers: make(map[string]bool)}
+	logger := NewTestLogger(tb, "teste")
+
+	logger.Print(msg)
+	logger.Tag("tag1").Errorf("%v", msg)
+	logger.DebugLevel().Di().Println(msg)
+
+	if len(tb.logs) != 3 {
+		t.Fatal("wrong number of logs", tb.logs)
+	}
+	if !strings.HasSuffix(tb.logs[1], " - error - tag1 - benchmark log test") {
+		t.Fatalf("wrong log %q", tb.logs[1])
+	}
+	if strings.HasSuffix(tb.logs[0], "\n") {
+		t.Fatalf("log with new line %q", tb.logs[0])
+	}
+	expected := []string{"testlog_test.go:56", "testlog_test.go:57", "testlog_test.go:58"}
+	if strings.Join(tb.lines, ",") != strings.Join(expected, ",") {
+		t.Fatal("wrong lines", tb.lines)
+	}
+
+	tb.end()
+	logger.Print(msg)
+	if len(tb.logs) != 3 {
+		t.Fatal("logged after the end of the test", tb.logs)
+	}
+}
+
+func TestTBWriter(t *testing.T) {
+	logger := NewTestLogger(t, "teste")
+	logger.Tag("tag1").Print(msg)
+
+	done := make(chan struct{})
+	t.Run("sub", func(t *testing.T) {
+		sub := NewTestLogger(t, "sub")
+		go func() {
+			<-done
+			sub.Print("after the end of the test")
+			close(done)
+		}()
+		sub.Print(msg)
+	})
+	done <- struct{}{}
+	<-done
+
+	w := NewTBWriter(t)
+	w.Write([]byte("closed\n"))
+	w.Close()
+	n, err := w.Write([]byte("discarded\n"))
+	if err != nil || n != len("discarded\n") {
+		t.Fatal("write failed", n, err)
+	}
+}
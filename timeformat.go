@@ -0,0 +1,152 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"strconv"
+	"time"
+)
+
+// TimeLayout is the layout of the timestamp of the entries.
+type TimeLayout uint8
+
+const (
+	// TimeDefault is the default layout of each formatter: TimeLegacy for the
+	// text formatter and TimeRFC3339 with nanoseconds for JSON and Logfmt.
+	TimeDefault TimeLayout = iota
+	// TimeLegacy is the layout 2006/01/02 15:04:05.
+	TimeLegacy
+	// TimeRFC3339 is the layout 2006-01-02T15:04:05Z07:00.
+	TimeRFC3339
+	// TimeRFC3339Nano is the layout 2006-01-02T15:04:05.999999999Z07:00, the
+	// trailing zeros of the fraction are removed.
+	TimeRFC3339Nano
+	// TimeUnix is the number of seconds since the Unix epoch.
+	TimeUnix
+	// TimeUnixMilli is the number of milliseconds since the Unix epoch.
+	TimeUnixMilli
+	// TimeUnixNano is the number of nanoseconds since the Unix epoch.
+	TimeUnixNano
+	// TimeCustom uses TimeFormat.Custom as a layout of the time package.
+	TimeCustom
+)
+
+// TimeFormat configures the timestamp of the entries. All formatters use it,
+// the syslog formatters only use UTC because the layout is defined by the
+// protocol.
+//
+//	logger := &slog.Slog{
+//		Time: slog.TimeFormat{Layout: slog.TimeRFC3339, UTC: true, Precision: 3},
+//	}
+type TimeFormat struct {
+	// Layout is the timestamp layout.
+	Layout TimeLayout
+	// Custom is the layout used with TimeCustom, like the ones of the time
+	// package.
+	Custom string
+	// UTC formats the time in UTC instead of the local time.
+	UTC bool
+	// Precision is the number of digits of the fraction of second used with
+	// TimeLegacy, TimeRFC3339 and TimeUnix, from 0 to 9.
+	Precision int
+}
+
+var pow10 = [...]int{1e9, 1e8, 1e7, 1e6, 1e5, 1e4, 1e3, 1e2, 1e1, 1}
+
+// appendFraction appends the fraction of second with prec digits.
+func appendFraction(buf *[]byte, nsec int, prec int) {
+	if prec <= 0 {
+		return
+	}
+	if prec > 9 {
+		prec = 9
+	}
+	*buf = append(*buf, '.')
+	Itoa(buf, nsec/pow10[prec], prec)
+}
+
+func appendDate(buf *[]byte, t time.Time, sep byte) {
+	year, month, day := t.Date()
+	Itoa(buf, year, 4)
+	*buf = append(*buf, sep)
+	Itoa(buf, int(month), 2)
+	*buf = append(*buf, sep)
+	Itoa(buf, day, 2)
+}
+
+func appendClock(buf *[]byte, t time.Time) {
+	hour, min, sec := t.Clock()
+	Itoa(buf, hour, 2)
+	*buf = append(*buf, ':')
+	Itoa(buf, min, 2)
+	*buf = append(*buf, ':')
+	Itoa(buf, sec, 2)
+}
+
+// appendRFC3339 appends t in the RFC 3339 layout. If prec is negative the
+// fraction has nine digits without the trailing zeros.
+func appendRFC3339(buf *[]byte, t time.Time, prec int) {
+	appendDate(buf, t, '-')
+	*buf = append(*buf, 'T')
+	appendClock(buf, t)
+	if prec < 0 {
+		dot := len(*buf)
+		appendFraction(buf, t.Nanosecond(), 9)
+		end := len(*buf)
+		for end > dot+1 && (*buf)[end-1] == '0' {
+			end--
+		}
+		if end == dot+1 {
+			end = dot
+		}
+		*buf = (*buf)[:end]
+	} else {
+		appendFraction(buf, t.Nanosecond(), prec)
+	}
+	*buf = append(*buf, zoneOf(t)...)
+}
+
+// AppendTime appends the timestamp t to buf with the format f. def is the
+// layout used if f.Layout is TimeDefault. The zone offset is the one of t,
+// Z if t is in UTC.
+func (f *TimeFormat) AppendTime(buf *[]byte, t time.Time, def TimeLayout) {
+	layout := f.Layout
+	prec := f.Precision
+	if layout == TimeDefault {
+		layout = def
+		prec = 0
+		if def == TimeRFC3339 {
+			prec = 9
+		}
+	}
+	if f.UTC {
+		t = t.UTC()
+	}
+	switch layout {
+	case TimeRFC3339:
+		appendRFC3339(buf, t, prec)
+	case TimeRFC3339Nano:
+		appendRFC3339(buf, t, -1)
+	case TimeUnix:
+		*buf = strconv.AppendInt(*buf, t.Unix(), 10)
+		appendFraction(buf, t.Nanosecond(), prec)
+	case TimeUnixMilli:
+		*buf = strconv.AppendInt(*buf, t.UnixNano()/1e6, 10)
+	case TimeUnixNano:
+		*buf = strconv.AppendInt(*buf, t.UnixNano(), 10)
+	case TimeCustom:
+		*buf = t.AppendFormat(*buf, f.Custom)
+	default:
+		appendDate(buf, t, '/')
+		*buf = append(*buf, ' ')
+		appendClock(buf, t)
+		appendFraction(buf, t.Nanosecond(), prec)
+	}
+}
+
+// appendTime appends the timestamp of the entry with the Slog TimeFormat.
+func (l *Slog) appendTime(buf *[]byte, def TimeLayout) {
+	l.Time.AppendTime(buf, l.Log.Timestamp, def)
+}
@@ -0,0 +1,128 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fcavani/e"
+	. "github.com/fcavani/slog"
+)
+
+func TestAppendTime(t *testing.T) {
+	zone := time.FixedZone("BRT", -3*3600)
+	ts := time.Date(2018, 3, 4, 5, 6, 7, 120000000, zone)
+	tests := []struct {
+		Format TimeFormat
+		Def    TimeLayout
+		Str    string
+	}{
+		{TimeFormat{}, TimeLegacy, "2018/03/04 05:06:07"},
+		{TimeFormat{}, TimeRFC3339, "2018-03-04T05:06:07.120000000-03:00"},
+		{TimeFormat{Layout: TimeLegacy, Precision: 3}, TimeRFC3339, "2018/03/04 05:06:07.120"},
+		{TimeFormat{Layout: TimeRFC3339}, TimeLegacy, "2018-03-04T05:06:07-03:00"},
+		{TimeFormat{Layout: TimeRFC3339, Precision: 6}, TimeLegacy, "2018-03-04T05:06:07.120000-03:00"},
+		{TimeFormat{Layout: TimeRFC3339, UTC: true}, TimeLegacy, "2018-03-04T08:06:07Z"},
+		{TimeFormat{Layout: TimeRFC3339Nano}, TimeLegacy, "2018-03-04T05:06:07.12-03:00"},
+		{TimeFormat{Layout: TimeRFC3339Nano, UTC: true, Precision: 3}, TimeLegacy, "2018-03-04T08:06:07.12Z"},
+		{TimeFormat{Layout: TimeUnix}, TimeLegacy, "1520150767"},
+		{TimeFormat{Layout: TimeUnix, Precision: 2}, TimeLegacy, "1520150767.12"},
+		{TimeFormat{Layout: TimeUnixMilli}, TimeLegacy, "1520150767120"},
+		{TimeFormat{Layout: TimeUnixNano}, TimeLegacy, "1520150767120000000"},
+		{TimeFormat{Layout: TimeCustom, Custom: "02 Jan 06 15:04 MST"}, TimeLegacy, "04 Mar 18 05:06 BRT"},
+		{TimeFormat{Layout: TimeLegacy, UTC: true}, TimeRFC3339, "2018/03/04 08:06:07"},
+	}
+	for i, test := range tests {
+		buf := make([]byte, 0, 64)
+		test.Format.AppendTime(&buf, ts, test.Def)
+		if string(buf) != test.Str {
+			t.Fatalf("%v: wrong time %q != %q", i, buf, test.Str)
+		}
+	}
+
+	buf := make([]byte, 0, 64)
+	(&TimeFormat{Layout: TimeRFC3339Nano}).AppendTime(&buf, time.Date(2018, 3, 4, 5, 6, 7, 0, zone), TimeLegacy)
+	if string(buf) != "2018-03-04T05:06:07-03:00" {
+		t.Fatalf("wrong time %q", buf)
+	}
+}
+
+func TestAppendTimeAllocs(t *testing.T) {
+	ts := time.Now()
+	buf := make([]byte, 0, 64)
+	for _, f := range []TimeFormat{
+		{Layout: TimeLegacy, Precision: 3},
+		{Layout: TimeRFC3339, UTC: true},
+		{Layout: TimeRFC3339Nano},
+		{Layout: TimeUnix, Precision: 6},
+		{Layout: TimeCustom, Custom: time.Kitchen},
+	} {
+		allocs := testing.AllocsPerRun(100, func() {
+			buf = buf[:0]
+			f.AppendTime(&buf, ts, TimeLegacy)
+		})
+		if allocs != 0 {
+			t.Fatal("AppendTime allocated", f.Layout, allocs)
+		}
+	}
+}
+
+func TestTimeFormatFormatters(t *testing.T) {
+	buf := &writerCloser{bytes.NewBuffer([]byte{})}
+
+	logger := &Slog{
+		Writter: buf,
+		Time:    TimeFormat{Layout: TimeUnix},
+	}
+	err := logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	logger.Print(msg)
+	fields := strings.Split(buf.String(), " - ")
+	buf.Reset()
+	if len(fields) != 4 || strings.ContainsAny(fields[1], "/:") {
+		t.Fatalf("wrong timestamp %q", fields)
+	}
+
+	logger = &Slog{
+		Writter:   buf,
+		Formatter: Logfmt,
+		Time:      TimeFormat{Layout: TimeLegacy, UTC: true},
+	}
+	err = logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	logger.Print(msg)
+	if !strings.HasPrefix(buf.String(), `time="`) {
+		t.Fatalf("timestamp isn't quoted: %q", buf.String())
+	}
+	buf.Reset()
+
+	logger = &Slog{
+		Writter:   buf,
+		Formatter: JSON,
+		Time:      TimeFormat{Layout: TimeRFC3339, UTC: true, Precision: 3},
+	}
+	err = logger.Init("teste", 1)
+	if err != nil {
+		t.Fatal(e.Trace(e.Forward(err)))
+	}
+	logger.Tag("tag1").MakeDefault().Print(msg)
+	s := buf.String()
+	i := strings.Index(s, `"Timestamp":"`)
+	if i < 0 {
+		t.Fatal("no timestamp", s)
+	}
+	stamp := s[i+len(`"Timestamp":"`):]
+	stamp = stamp[:strings.IndexByte(stamp, '"')]
+	if len(stamp) != len("2006-01-02T15:04:05.000Z") || stamp[len(stamp)-1] != 'Z' {
+		t.Fatalf("wrong timestamp %q", stamp)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var utcZone = []byte("Z")
+
+type zoneCache struct {
+	offset int
+	buf    []byte
+}
+
+// lastZone caches the last zone offset formatted, so the offset is formatted
+// again only when it changes, like in the daylight saving time transitions.
+var lastZone atomic.Value
+
+// formatZone formats the offset, in seconds east of UTC, like +03:00.
+func formatZone(offset int) []byte {
+	buf := make([]byte, 0, 6)
+	if offset < 0 {
+		buf = append(buf, '-')
+		offset = -offset
+	} else {
+		buf = append(buf, '+')
+	}
+	Itoa(&buf, offset/3600, 2)
+	buf = append(buf, ':')
+	Itoa(&buf, offset%3600/60, 2)
+	return buf
+}
+
+// zoneOf returns the zone offset of t in the form +03:00 or Z for UTC. The
+// returned slice must not be modified.
+func zoneOf(t time.Time) []byte {
+	_, offset := t.Zone()
+	if offset == 0 {
+		return utcZone
+	}
+	if c, ok := lastZone.Load().(*zoneCache); ok && c.offset == offset {
+		return c.buf
+	}
+	c := &zoneCache{
+		offset: offset,
+		buf:    formatZone(offset),
+	}
+	lastZone.Store(c)
+	return c.buf
+}
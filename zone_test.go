@@ -0,0 +1,77 @@
+// Copyright 2018 Felipe A. Cavani. All rights reserved.
+// Use of this source code is governed by the Apache License 2.0
+// license that can be found in the LICENSE file.
+
+package slog_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	. "github.com/fcavani/slog"
+)
+
+func appendTime(f TimeFormat, t time.Time) string {
+	buf := make([]byte, 0, 64)
+	f.AppendTime(&buf, t, TimeLegacy)
+	return string(buf)
+}
+
+func TestZoneDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := TimeFormat{Layout: TimeRFC3339}
+
+	// 2018-03-11 02:00 EST the clocks go forward to 03:00 EDT.
+	before := time.Date(2018, 3, 11, 6, 59, 59, 0, time.UTC).In(loc)
+	after := before.Add(time.Second)
+	if s := appendTime(f, before); s != "2018-03-11T01:59:59-05:00" {
+		t.Fatal("wrong time before the transition", s)
+	}
+	if s := appendTime(f, after); s != "2018-03-11T03:00:00-04:00" {
+		t.Fatal("wrong time after the transition", s)
+	}
+	// The cached offset must not leak to the other side of the transition.
+	if s := appendTime(f, before); s != "2018-03-11T01:59:59-05:00" {
+		t.Fatal("wrong time before the transition", s)
+	}
+
+	// 2018-11-04 02:00 EDT the clocks go back to 01:00 EST.
+	before = time.Date(2018, 11, 4, 5, 59, 59, 0, time.UTC).In(loc)
+	after = before.Add(time.Second)
+	if s := appendTime(f, before); s != "2018-11-04T01:59:59-04:00" {
+		t.Fatal("wrong time before the transition", s)
+	}
+	if s := appendTime(f, after); s != "2018-11-04T01:00:00-05:00" {
+		t.Fatal("wrong time after the transition", s)
+	}
+
+	f.UTC = true
+	if s := appendTime(f, after); s != "2018-11-04T06:00:00Z" {
+		t.Fatal("wrong time in UTC", s)
+	}
+}
+
+func TestZoneOffsets(t *testing.T) {
+	f := TimeFormat{Layout: TimeRFC3339}
+	tests := []struct {
+		Loc *time.Location
+		Str string
+	}{
+		{time.UTC, "2018-03-04T05:06:07Z"},
+		{time.FixedZone("", 0), "2018-03-04T05:06:07Z"},
+		{time.FixedZone("IST", 5*3600+30*60), "2018-03-04T05:06:07+05:30"},
+		{time.FixedZone("NST", -(3*3600 + 30*60)), "2018-03-04T05:06:07-03:30"},
+		{time.FixedZone("BRT", -3*3600), "2018-03-04T05:06:07-03:00"},
+		{time.FixedZone("IST", 5*3600+30*60), "2018-03-04T05:06:07+05:30"},
+	}
+	for _, test := range tests {
+		ts := time.Date(2018, 3, 4, 5, 6, 7, 0, test.Loc)
+		if s := appendTime(f, ts); s != test.Str {
+			t.Fatalf("wrong time %q != %q", s, test.Str)
+		}
+	}
+}